@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"yadro.com/course/events"
 )
 
 func main() {
@@ -27,9 +28,14 @@ func main() {
 	for {
 		select {
 		case <-ticker.C:
-			// Отправляем сообщение в топик "xkcd.updates"
+			// Отправляем событие в топик "xkcd.db.updated"
 			slog.Info("sending message to subscribers")
-			err = nc.Publish("xkcd.db.updated", []byte("XKCD DB has been updated"))
+			data, err := events.Encode(events.DBChanged{Type: events.TypeComicsStored, Added: []int{1, 2, 3}})
+			if err != nil {
+				slog.Error("could not encode event", "error", err)
+				continue
+			}
+			err = nc.Publish(events.SubjectDBChanged, data)
 			if err != nil {
 				slog.Error("could not publish message", "error", err)
 			}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 
 	"github.com/nats-io/nats.go"
+	"yadro.com/course/events"
 )
 
 func main() {
@@ -18,7 +19,7 @@ func main() {
 	defer nc.Close()
 
 	// Синхронное получение сообщений
-	sub, err := nc.SubscribeSync("xkcd.db.updated")
+	sub, err := nc.SubscribeSync(events.SubjectDBChanged)
 	if err != nil {
 		panic(err)
 	}
@@ -32,10 +33,22 @@ func main() {
 			slog.Error("cannot get next message", "error", err)
 			break
 		}
-		slog.Info("received message", "data", msg.Data)
+		logEvent(msg.Data)
 	}
 
 	if err = sub.Unsubscribe(); err != nil {
 		panic(err)
 	}
 }
+
+// logEvent выводит полученное событие, старые текстовые сообщения
+// выводятся как события типа legacy
+func logEvent(data []byte) {
+	event, err := events.Decode(data)
+	if err != nil {
+		slog.Error("cannot decode event", "data", string(data), "error", err)
+		return
+	}
+	slog.Info("received event", "type", event.Type, "job", event.JobID,
+		"added", event.Added, "updated", event.Updated, "deleted", event.Deleted)
+}
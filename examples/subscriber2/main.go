@@ -7,6 +7,7 @@ import (
 	"os/signal"
 
 	"github.com/nats-io/nats.go"
+	"yadro.com/course/events"
 )
 
 func main() {
@@ -21,8 +22,8 @@ func main() {
 	defer cancel()
 
 	// Асинхронное получение сообщений
-	sub, err := nc.Subscribe("xkcd.db.updated", func(msg *nats.Msg) {
-		slog.Info("received message", "data", msg.Data)
+	sub, err := nc.Subscribe(events.SubjectDBChanged, func(msg *nats.Msg) {
+		logEvent(msg.Data)
 	})
 
 	if err != nil {
@@ -35,3 +36,15 @@ func main() {
 		panic(err)
 	}
 }
+
+// logEvent выводит полученное событие, старые текстовые сообщения
+// выводятся как события типа legacy
+func logEvent(data []byte) {
+	event, err := events.Decode(data)
+	if err != nil {
+		slog.Error("cannot decode event", "data", string(data), "error", err)
+		return
+	}
+	slog.Info("received event", "type", event.Type, "job", event.JobID,
+		"added", event.Added, "updated", event.Updated, "deleted", event.Deleted)
+}
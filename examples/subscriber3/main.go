@@ -7,6 +7,7 @@ import (
 	"os/signal"
 
 	"github.com/nats-io/nats.go"
+	"yadro.com/course/events"
 )
 
 func main() {
@@ -22,7 +23,7 @@ func main() {
 
 	// Получение сообщений в канал с буфером на 10
 	ch := make(chan *nats.Msg, 10)
-	sub, err := nc.ChanSubscribe("xkcd.db.updated", ch)
+	sub, err := nc.ChanSubscribe(events.SubjectDBChanged, ch)
 	if err != nil {
 		panic(err)
 	}
@@ -35,7 +36,19 @@ func main() {
 			}
 			return
 		case msg := <-ch:
-			slog.Info("message received", "data", msg.Data)
+			logEvent(msg.Data)
 		}
 	}
 }
+
+// logEvent выводит полученное событие, старые текстовые сообщения
+// выводятся как события типа legacy
+func logEvent(data []byte) {
+	event, err := events.Decode(data)
+	if err != nil {
+		slog.Error("cannot decode event", "data", string(data), "error", err)
+		return
+	}
+	slog.Info("received event", "type", event.Type, "job", event.JobID,
+		"added", event.Added, "updated", event.Updated, "deleted", event.Deleted)
+}
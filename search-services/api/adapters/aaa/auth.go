@@ -47,6 +47,7 @@ func (a AAA) Login(name, password string) (string, error) {
 		"exp":        time.Now().Add(a.tokenTTL).Unix(),
 		"authorized": true,
 		"sub":        adminRole,
+		"name":       name,
 	})
 
 	tokenString, err := token.SignedString([]byte(secretKey))
@@ -56,7 +57,7 @@ func (a AAA) Login(name, password string) (string, error) {
 	return tokenString, nil
 }
 
-func (a AAA) Verify(tokenString string) error {
+func (a AAA) Verify(tokenString string) (string, error) {
 	token, err := jwt.Parse(
 		tokenString,
 		func(token *jwt.Token) (interface{}, error) {
@@ -69,29 +70,32 @@ func (a AAA) Verify(tokenString string) error {
 	)
 
 	if err != nil {
-		return core.ErrUnauthorized
+		return "", core.ErrUnauthorized
 	}
 
 	if !token.Valid {
-		return core.ErrUnauthorized
+		return "", core.ErrUnauthorized
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok {
 		if exp, ok := claims["exp"].(float64); ok {
 			expTime := time.Unix(int64(exp), 0)
 			if time.Now().After(expTime) {
-				return core.ErrUnauthorized
+				return "", core.ErrUnauthorized
 			}
 		}
 
 		if authorized, ok := claims["authorized"].(bool); !ok || !authorized {
-			return core.ErrUnauthorized
+			return "", core.ErrUnauthorized
 		}
 
 		if sub, ok := claims["sub"].(string); !ok || sub != adminRole {
-			return core.ErrUnauthorized
+			return "", core.ErrUnauthorized
 		}
+
+		name, _ := claims["name"].(string)
+		return name, nil
 	}
 
-	return nil
+	return "", nil
 }
@@ -1,11 +1,14 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"yadro.com/course/api/core"
 )
@@ -21,6 +24,11 @@ func NewPingHandler(log *slog.Logger, pingers map[string]core.Pinger) http.Handl
 
 		for name, pinger := range pingers {
 			if err := pinger.Ping(r.Context()); err != nil {
+				if errors.Is(err, core.ErrNotReady) {
+					response.Answer[name] = "not_ready"
+					log.Warn("service is not ready", "service", name)
+					continue
+				}
 				response.Answer[name] = "unavailable"
 				log.Error("service is not available", "service", name)
 				continue
@@ -67,25 +75,229 @@ func NewWordsHandler(log *slog.Logger, norm core.Normalizer) http.HandlerFunc {
 	}
 }
 
+type JobReply struct {
+	ID          int64        `json:"id"`
+	Kind        string       `json:"kind"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  time.Time    `json:"finished_at,omitzero"`
+	Outcome     string       `json:"outcome"`
+	Total       int          `json:"total"`
+	Fetched     int          `json:"fetched"`
+	Normalized  int          `json:"normalized"`
+	Stored      int          `json:"stored"`
+	Failed      int          `json:"failed"`
+	Changed     int          `json:"changed"`
+	Timings     TimingsReply `json:"timings"`
+	Error       string       `json:"error,omitempty"`
+	TriggeredBy string       `json:"triggered_by,omitempty"`
+}
+
+// TimingsReply is the time in milliseconds spent by workers of every stage of
+// an update job, summed over workers.
+type TimingsReply struct {
+	FetchMS     int64 `json:"fetch_ms"`
+	NormalizeMS int64 `json:"normalize_ms"`
+	StoreMS     int64 `json:"store_ms"`
+}
+
+func newTimingsReply(timings core.StageTimings) TimingsReply {
+	return TimingsReply{
+		FetchMS:     timings.Fetch.Milliseconds(),
+		NormalizeMS: timings.Normalize.Milliseconds(),
+		StoreMS:     timings.Store.Milliseconds(),
+	}
+}
+
+func newJobReply(job core.Job) JobReply {
+	return JobReply{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		Outcome:     string(job.Outcome),
+		Total:       job.Total,
+		Fetched:     job.Fetched,
+		Normalized:  job.Normalized,
+		Stored:      job.Stored,
+		Failed:      job.Failed,
+		Changed:     job.Changed,
+		Timings:     newTimingsReply(job.Timings),
+		Error:       job.Error,
+		TriggeredBy: job.TriggeredBy,
+	}
+}
+
+// NewUpdateHandler starts fetching missing comics, or with mode=refresh
+// checking stored comics for upstream changes, optionally only those
+// published within the last days or with empty_transcript.
 func NewUpdateHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := updater.Update(r.Context())
+		switch r.URL.Query().Get("mode") {
+		case "", "missing":
+			jobHandlerCommon(log, updater.Update)(w, r)
+		case "refresh":
+			filter, err := refreshFilter(r)
+			if err != nil {
+				log.Error("Wrong refresh params from rest", "error", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			jobHandlerCommon(log, func(ctx context.Context, triggeredBy string) (core.Job, error) {
+				return updater.Refresh(ctx, triggeredBy, filter)
+			})(w, r)
+		default:
+			http.Error(w, "mode should be missing or refresh", http.StatusBadRequest)
+		}
+	}
+}
+
+func refreshFilter(r *http.Request) (core.RefreshFilter, error) {
+	days, err := intParam(r, "days", 0)
+	if err != nil || days < 0 {
+		return core.RefreshFilter{}, errors.New("days should be not negative integer")
+	}
+	filter := core.RefreshFilter{Days: days}
+	if raw := r.URL.Query().Get("empty_transcript"); raw != "" {
+		if filter.EmptyTranscript, err = strconv.ParseBool(raw); err != nil {
+			return core.RefreshFilter{}, errors.New("empty_transcript should be boolean")
+		}
+	}
+	return filter, nil
+}
+
+func NewRetryFailedHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return jobHandlerCommon(log, updater.RetryFailed)
+}
+
+func NewReprocessHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return jobHandlerCommon(log, updater.Reprocess)
+}
+
+func jobHandlerCommon(
+	log *slog.Logger, start func(ctx context.Context, triggeredBy string) (core.Job, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, _ := core.UserFromContext(r.Context())
+		job, err := start(r.Context(), user)
 		if err != nil {
-			if err.Error() == core.ErrAlreadyExists.Error() {
-				w.WriteHeader(http.StatusAccepted)
-				_, err := w.Write([]byte("Request accepted for processing"))
-				if err != nil {
-					log.Error("Strange error about response", "error", err)
+			if errors.Is(err, core.ErrAlreadyExists) {
+				// the job runs on another replica, there is nothing to return
+				if job.ID == 0 {
+					http.Error(w, err.Error(), http.StatusConflict)
+					return
+				}
+				w.WriteHeader(http.StatusConflict)
+				if err := json.NewEncoder(w).Encode(newJobReply(job)); err != nil {
+					log.Error("server cannot make reply update request", "error", err)
 				}
 				return
 			}
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			log.Error("Failed to answer update rest request", "error", err)
 			http.Error(w, "Error in server"+err.Error(), http.StatusInternalServerError)
 			return
 		}
-		w.WriteHeader(http.StatusOK)
-		_, err = w.Write([]byte("Request accepted for processing"))
+		w.Header().Set("Location", fmt.Sprintf("/api/db/jobs/%d", job.ID))
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(newJobReply(job)); err != nil {
+			log.Error("server cannot make reply update request", "error", err)
+		}
+	}
+}
+
+type JobsResponse struct {
+	Jobs   []JobReply `json:"jobs"`
+	Offset int        `json:"offset"`
+	Limit  int        `json:"limit"`
+}
+
+func NewJobsHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		offset, err := intParam(r, "offset", 0)
+		if err != nil || offset < 0 {
+			log.Error("Wrong offset param from rest", "error", err)
+			http.Error(w, "offset should be not negative integer", http.StatusBadRequest)
+			return
+		}
+		limit, err := intParam(r, "limit", 10)
+		if err != nil || limit <= 0 {
+			log.Error("Wrong limit param from rest", "error", err)
+			http.Error(w, "limit should be positive integer", http.StatusBadRequest)
+			return
+		}
+
+		jobs, err := updater.ListJobs(r.Context(), limit, offset)
+		if err != nil {
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Error("Cannot answer jobs request in rest", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := JobsResponse{Jobs: make([]JobReply, len(jobs)), Offset: offset, Limit: limit}
+		for i, job := range jobs {
+			response.Jobs[i] = newJobReply(job)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("server cannot make reply jobs request", "error", err)
+		}
+	}
+}
+
+func NewJobHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Error("Wrong job id param from rest", "error", err)
+			http.Error(w, "id should be positive integer", http.StatusBadRequest)
+			return
+		}
+
+		job, err := updater.GetJob(r.Context(), id)
 		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				http.Error(w, "job is not found", http.StatusNotFound)
+				return
+			}
+			log.Error("Cannot answer job request in rest", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(newJobReply(job)); err != nil {
+			log.Error("server cannot make reply job request", "error", err)
+		}
+	}
+}
+
+func NewCancelJobHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Error("Wrong job id param from rest", "error", err)
+			http.Error(w, "id should be positive integer", http.StatusBadRequest)
+			return
+		}
+
+		if err := updater.CancelJob(r.Context(), id); err != nil {
+			switch {
+			case errors.Is(err, core.ErrNotFound):
+				http.Error(w, "job is not found", http.StatusNotFound)
+			case errors.Is(err, core.ErrJobFinished):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				log.Error("Cannot cancel job in rest", "error", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		if _, err := w.Write([]byte("Job cancellation requested")); err != nil {
 			log.Error("Strange error about response", "error", err)
 		}
 	}
@@ -96,6 +308,7 @@ type StatsReply struct {
 	WordsUnique   int `json:"words_unique"`
 	ComicsFetched int `json:"comics_fetched"`
 	ComicsTotal   int `json:"comics_total"`
+	ComicsFailed  int `json:"comics_failed"`
 }
 
 func NewUpdateStatsHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
@@ -111,6 +324,7 @@ func NewUpdateStatsHandler(log *slog.Logger, updater core.Updater) http.HandlerF
 			WordsUnique:   reply.WordsUnique,
 			ComicsFetched: reply.ComicsFetched,
 			ComicsTotal:   reply.ComicsTotal,
+			ComicsFailed:  reply.ComicsFailed,
 		}
 		if err := json.NewEncoder(w).Encode(result); err != nil {
 			log.Error("server cannot make reply status", "error", err)
@@ -118,30 +332,178 @@ func NewUpdateStatsHandler(log *slog.Logger, updater core.Updater) http.HandlerF
 	}
 }
 
+type FailedComicsReply struct {
+	ID         int       `json:"id"`
+	Stage      string    `json:"stage"`
+	ErrorClass string    `json:"error_class"`
+	Error      string    `json:"error"`
+	Attempts   int       `json:"attempts"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+type FailedResponse struct {
+	Comics []FailedComicsReply `json:"comics"`
+	Total  int                 `json:"total"`
+}
+
+func NewFailedHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		failed, err := updater.ListFailed(r.Context())
+		if err != nil {
+			log.Error("Failed comics cannot be gotten", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := FailedResponse{Comics: make([]FailedComicsReply, len(failed)), Total: len(failed)}
+		for i, f := range failed {
+			response.Comics[i] = FailedComicsReply{
+				ID:         f.ID,
+				Stage:      f.Stage,
+				ErrorClass: f.ErrorClass,
+				Error:      f.Error,
+				Attempts:   f.Attempts,
+				FailedAt:   f.FailedAt,
+			}
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("server cannot make reply failed comics", "error", err)
+		}
+	}
+}
+
+type QuarantinedComicsReply struct {
+	ID            int       `json:"id"`
+	Payload       string    `json:"payload"`
+	Reason        string    `json:"reason"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+type QuarantineResponse struct {
+	Comics []QuarantinedComicsReply `json:"comics"`
+	Total  int                      `json:"total"`
+}
+
+func NewQuarantineHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		quarantined, err := updater.ListQuarantined(r.Context())
+		if err != nil {
+			log.Error("Quarantined comics cannot be gotten", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := QuarantineResponse{
+			Comics: make([]QuarantinedComicsReply, len(quarantined)),
+			Total:  len(quarantined),
+		}
+		for i, q := range quarantined {
+			response.Comics[i] = QuarantinedComicsReply{
+				ID:            q.ID,
+				Payload:       q.Payload,
+				Reason:        q.Reason,
+				QuarantinedAt: q.QuarantinedAt,
+			}
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("server cannot make reply quarantined comics", "error", err)
+		}
+	}
+}
+
 type StatusReply struct {
-	Status string `json:"status"`
+	Status    string    `json:"status"`
+	LastRun   time.Time `json:"last_run,omitzero"`
+	NextRun   time.Time `json:"next_run,omitzero"`
+	Holder    string    `json:"holder,omitempty"`
+	HeldSince time.Time `json:"held_since,omitzero"`
 }
 
 func NewUpdateStatusHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		statusReply, err := updater.Status(r.Context())
+		state, err := updater.Status(r.Context())
 		if err != nil {
 			log.Error("Status cannot be gotten", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		result := StatusReply{}
-		result.Status = string(statusReply)
+		result := StatusReply{
+			Status:    string(state.Status),
+			LastRun:   state.LastRun,
+			NextRun:   state.NextRun,
+			Holder:    state.Holder,
+			HeldSince: state.HeldSince,
+		}
 		if err := json.NewEncoder(w).Encode(result); err != nil {
 			log.Error("server cannot make reply status", "error", err)
 		}
 	}
 }
 
+type ProgressReply struct {
+	Status     string       `json:"status"`
+	Total      int          `json:"total"`
+	Fetched    int          `json:"fetched"`
+	Normalized int          `json:"normalized"`
+	Stored     int          `json:"stored"`
+	Failed     int          `json:"failed"`
+	Changed    int          `json:"changed"`
+	CurrentID  int          `json:"current_id"`
+	Timings    TimingsReply `json:"timings"`
+}
+
+func NewUpdateEventsHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events, err := updater.WatchUpdate(r.Context())
+		if err != nil {
+			log.Error("Update progress cannot be watched", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for progress := range events {
+			data, err := json.Marshal(ProgressReply{
+				Status:     string(progress.Status),
+				Total:      progress.Total,
+				Fetched:    progress.Fetched,
+				Normalized: progress.Normalized,
+				Stored:     progress.Stored,
+				Failed:     progress.Failed,
+				Changed:    progress.Changed,
+				CurrentID:  progress.CurrentID,
+				Timings:    newTimingsReply(progress.Timings),
+			})
+			if err != nil {
+				log.Error("server cannot make progress event", "error", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+				log.Error("server cannot send progress event", "error", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
 func NewDropHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := updater.Drop(r.Context())
 		if err != nil {
+			if errors.Is(err, core.ErrAlreadyExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
 			log.Error("Stats cannot be gotten", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -154,8 +516,132 @@ func NewDropHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
 	}
 }
 
+// NewRefetchHandler starts downloading again comics given by idRange.
+func NewRefetchHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := idRange(r)
+		if err != nil {
+			log.Error("Wrong comics ids from rest", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobHandlerCommon(log, func(ctx context.Context, triggeredBy string) (core.Job, error) {
+			return updater.Refetch(ctx, triggeredBy, from, to)
+		})(w, r)
+	}
+}
+
+type DeleteReply struct {
+	Deleted int `json:"deleted"`
+}
+
+// NewDeleteHandler deletes comics given by idRange.
+func NewDeleteHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := idRange(r)
+		if err != nil {
+			log.Error("Wrong comics ids from rest", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		deleted, err := updater.Delete(r.Context(), from, to)
+		if err != nil {
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, core.ErrAlreadyExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			log.Error("Comics cannot be deleted", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(DeleteReply{Deleted: deleted}); err != nil {
+			log.Error("server cannot make reply delete request", "error", err)
+		}
+	}
+}
+
+// idRange takes comics ids either from the {id} path value or from the
+// required from and to query params.
+func idRange(r *http.Request) (int, int, error) {
+	if raw := r.PathValue("id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 1 {
+			return 0, 0, errors.New("id should be positive integer")
+		}
+		return id, id, nil
+	}
+	from, err := intParam(r, "from", 0)
+	if err != nil || from < 1 {
+		return 0, 0, errors.New("from should be positive integer")
+	}
+	to, err := intParam(r, "to", 0)
+	if err != nil || to < from {
+		return 0, 0, errors.New("to should be integer not less than from")
+	}
+	return from, to, nil
+}
+
+type RestoreReply struct {
+	Restored int `json:"restored"`
+}
+
+// NewRestoreHandler brings back comics dropped with DELETE /api/db.
+func NewRestoreHandler(log *slog.Logger, updater core.Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		restored, err := updater.Restore(r.Context())
+		if err != nil {
+			if errors.Is(err, core.ErrAlreadyExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			log.Error("Comics cannot be restored", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(RestoreReply{Restored: restored}); err != nil {
+			log.Error("server cannot make reply restore request", "error", err)
+		}
+	}
+}
+
+type ComicsReply struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	SafeTitle  string `json:"safe_title,omitempty"`
+	Alt        string `json:"alt,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	Published  string `json:"published,omitempty"`
+	// search results only
+	Score   float64  `json:"score,omitempty"`
+	Matched []string `json:"matched,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+func newComicsReply(comic core.Comics) ComicsReply {
+	reply := ComicsReply{
+		ID:         comic.ID,
+		URL:        comic.URL,
+		Title:      comic.Title,
+		SafeTitle:  comic.SafeTitle,
+		Alt:        comic.Alt,
+		Transcript: comic.Transcript,
+		Score:      comic.Score,
+		Matched:    comic.Matched,
+		Missing:    comic.Missing,
+	}
+	if !comic.Published.IsZero() {
+		reply.Published = comic.Published.Format(time.DateOnly)
+	}
+	return reply
+}
+
 type SearchResponse struct {
-	Comics []core.Comics `json:"comics"`
+	Comics []ComicsReply `json:"comics"`
 	Total  int           `json:"total"`
 }
 
@@ -198,17 +684,141 @@ func searchHandlerCommon(log *slog.Logger, searcher core.Searcher, withIndex boo
 		}
 
 		if err != nil {
+			if errors.Is(err, core.ErrNotReady) {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
 			log.Error("Cannot answer search request in rest", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		response := SearchResponse{Comics: answer, Total: len(answer)}
+		response := SearchResponse{Comics: make([]ComicsReply, len(answer)), Total: len(answer)}
+		for i, comic := range answer {
+			response.Comics[i] = newComicsReply(comic)
+		}
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			log.Error("server cannot make reply search request", "error", err)
 		}
 	}
 }
 
+func NewComicsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || id <= 0 {
+			log.Error("Wrong comics id param from rest", "error", err)
+			http.Error(w, "id should be positive integer", http.StatusBadRequest)
+			return
+		}
+
+		comic, err := searcher.Comics(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				http.Error(w, "comics is not found", http.StatusNotFound)
+				return
+			}
+			log.Error("Cannot answer comics request in rest", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(newComicsReply(comic)); err != nil {
+			log.Error("server cannot make reply comics request", "error", err)
+		}
+	}
+}
+
+type IndexStatsReply struct {
+	Generation uint64    `json:"generation"`
+	BuiltAt    time.Time `json:"built_at"`
+	Words      int       `json:"words"`
+	Comics     int       `json:"comics"`
+}
+
+func NewIndexStatsHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := searcher.IndexStats(r.Context())
+		if err != nil {
+			if errors.Is(err, core.ErrNotReady) {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			log.Error("Cannot answer index stats request in rest", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result := IndexStatsReply{
+			Generation: stats.Generation,
+			BuiltAt:    stats.BuiltAt,
+			Words:      stats.Words,
+			Comics:     stats.Comics,
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			log.Error("server cannot make reply index stats request", "error", err)
+		}
+	}
+}
+
+type ComicsListResponse struct {
+	Comics []ComicsReply `json:"comics"`
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+}
+
+func NewComicsListHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		offset, err := intParam(r, "offset", 0)
+		if err != nil || offset < 0 {
+			log.Error("Wrong offset param from rest", "error", err)
+			http.Error(w, "offset should be not negative integer", http.StatusBadRequest)
+			return
+		}
+		limit, err := intParam(r, "limit", 10)
+		if err != nil || limit <= 0 {
+			log.Error("Wrong limit param from rest", "error", err)
+			http.Error(w, "limit should be positive integer", http.StatusBadRequest)
+			return
+		}
+		order := r.URL.Query().Get("order")
+		if order != "" && order != "asc" && order != "desc" {
+			http.Error(w, "order should be asc or desc", http.StatusBadRequest)
+			return
+		}
+
+		page, err := searcher.List(r.Context(), offset, limit, order)
+		if err != nil {
+			if errors.Is(err, core.ErrBadArguments) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Error("Cannot answer comics list request in rest", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := ComicsListResponse{
+			Comics: make([]ComicsReply, len(page.Comics)),
+			Total:  page.Total,
+			Offset: offset,
+			Limit:  limit,
+		}
+		for i, comic := range page.Comics {
+			response.Comics[i] = newComicsReply(comic)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("server cannot make reply comics list request", "error", err)
+		}
+	}
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
+
 func NewLoginHandler(log *slog.Logger, loginer core.Loginer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		type Credentials struct {
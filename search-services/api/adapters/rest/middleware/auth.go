@@ -9,7 +9,7 @@ import (
 )
 
 type TokenVerifier interface {
-	Verify(token string) error
+	Verify(token string) (string, error)
 }
 
 func Auth(next http.HandlerFunc, verifier TokenVerifier) http.HandlerFunc {
@@ -28,7 +28,7 @@ func Auth(next http.HandlerFunc, verifier TokenVerifier) http.HandlerFunc {
 
 		token := strings.TrimPrefix(authParam, bearerPrefix)
 
-		err := verifier.Verify(token)
+		user, err := verifier.Verify(token)
 		if errors.Is(err, core.ErrUnauthorized) {
 			http.Error(w, "Authorization is not passed", http.StatusUnauthorized)
 			return
@@ -37,6 +37,6 @@ func Auth(next http.HandlerFunc, verifier TokenVerifier) http.HandlerFunc {
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(core.WithUser(r.Context(), user)))
 	}
 }
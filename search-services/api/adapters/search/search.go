@@ -5,7 +5,9 @@ import (
 	"log/slog"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"yadro.com/course/api/core"
 	searchpb "yadro.com/course/proto/search"
 )
@@ -33,7 +35,7 @@ func NewClient(address string, log *slog.Logger) (*Client, error) {
 
 func (c Client) Ping(ctx context.Context) error {
 	_, err := c.client.Ping(ctx, nil)
-	return err
+	return fromStatus(err)
 }
 
 func (c Client) Close() error {
@@ -59,12 +61,88 @@ func (c Client) searchCommon(ctx context.Context, phrase string, limit int, with
 	}
 	if err != nil {
 		c.log.Error("Failed to get response from search server", "error", err)
-		return nil, err
+		return nil, fromStatus(err)
 	}
 	result := make([]core.Comics, len(answer.Comics))
 	for index, comic := range answer.Comics {
-		result[index] = core.Comics{ID: int(comic.Id), URL: comic.Url}
+		result[index] = fromProto(comic)
 	}
 	c.log.Info("Response from search server has been recieved")
 	return result, nil
 }
+
+func (c Client) Comics(ctx context.Context, id int) (core.Comics, error) {
+	answer, err := c.client.GetComics(ctx, &searchpb.ComicsIdRequest{Id: int64(id)})
+	if err != nil {
+		c.log.Error("Failed to get comics from search server", "error", err)
+		return core.Comics{}, fromStatus(err)
+	}
+	return fromProto(answer), nil
+}
+
+func (c Client) IndexStats(ctx context.Context) (core.IndexStats, error) {
+	answer, err := c.client.IndexStats(ctx, nil)
+	if err != nil {
+		c.log.Error("Failed to get index stats from search server", "error", err)
+		return core.IndexStats{}, fromStatus(err)
+	}
+	return core.IndexStats{
+		Generation: answer.Generation,
+		BuiltAt:    answer.BuiltAt.AsTime(),
+		Words:      int(answer.Words),
+		Comics:     int(answer.Comics),
+	}, nil
+}
+
+func (c Client) List(ctx context.Context, offset, limit int, order string) (core.ComicsPage, error) {
+	request := &searchpb.ListComicsRequest{Offset: int64(offset), Limit: int64(limit)}
+	switch order {
+	case "", "asc":
+		request.Order = searchpb.Order_ORDER_ASC
+	case "desc":
+		request.Order = searchpb.Order_ORDER_DESC
+	default:
+		return core.ComicsPage{}, core.ErrBadArguments
+	}
+
+	answer, err := c.client.ListComics(ctx, request)
+	if err != nil {
+		c.log.Error("Failed to list comics in search server", "error", err)
+		return core.ComicsPage{}, fromStatus(err)
+	}
+	result := core.ComicsPage{Comics: make([]core.Comics, len(answer.Comics)), Total: int(answer.Total)}
+	for index, comic := range answer.Comics {
+		result.Comics[index] = fromProto(comic)
+	}
+	return result, nil
+}
+
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return core.ErrNotFound
+	case codes.InvalidArgument:
+		return core.ErrBadArguments
+	case codes.FailedPrecondition:
+		return core.ErrNotReady
+	}
+	return err
+}
+
+func fromProto(comic *searchpb.Comics) core.Comics {
+	result := core.Comics{
+		ID:         int(comic.Id),
+		URL:        comic.Url,
+		Title:      comic.Title,
+		SafeTitle:  comic.SafeTitle,
+		Alt:        comic.Alt,
+		Transcript: comic.Transcript,
+		Score:      comic.Score,
+		Matched:    comic.Matched,
+		Missing:    comic.Missing,
+	}
+	if comic.Published != nil {
+		result.Published = comic.Published.AsTime()
+	}
+	return result
+}
@@ -3,9 +3,11 @@ package update
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"yadro.com/course/api/core"
@@ -33,20 +35,32 @@ func (c Client) Ping(ctx context.Context) error {
 	return err
 }
 
-func (c Client) Status(ctx context.Context) (core.UpdateStatus, error) {
+func (c Client) Status(ctx context.Context) (core.UpdateState, error) {
 	result, err := c.client.Status(ctx, nil)
 	if err != nil {
-		return core.StatusUpdateUnknown, err
+		return core.UpdateState{Status: core.StatusUpdateUnknown}, err
 	}
-	switch result.Status {
-	case updatepb.Status_STATUS_UNSPECIFIED:
-		return core.StatusUpdateUnknown, nil
+	state := core.UpdateState{Status: fromStatus(result.Status), Holder: result.Holder}
+	if result.LastRun != nil {
+		state.LastRun = result.LastRun.AsTime()
+	}
+	if result.NextRun != nil {
+		state.NextRun = result.NextRun.AsTime()
+	}
+	if result.HeldSince != nil {
+		state.HeldSince = result.HeldSince.AsTime()
+	}
+	return state, nil
+}
+
+func fromStatus(status updatepb.Status) core.UpdateStatus {
+	switch status {
 	case updatepb.Status_STATUS_IDLE:
-		return core.StatusUpdateIdle, nil
+		return core.StatusUpdateIdle
 	case updatepb.Status_STATUS_RUNNING:
-		return core.StatusUpdateRunning, nil
+		return core.StatusUpdateRunning
 	}
-	return core.StatusUpdateUnknown, nil
+	return core.StatusUpdateUnknown
 }
 
 func (c Client) Stats(ctx context.Context) (core.UpdateStats, error) {
@@ -59,20 +73,245 @@ func (c Client) Stats(ctx context.Context) (core.UpdateStats, error) {
 		WordsUnique:   int(result.WordsUnique),
 		ComicsFetched: int(result.ComicsFetched),
 		ComicsTotal:   int(result.ComicsTotal),
+		ComicsFailed:  int(result.ComicsFailed),
 	}, nil
 }
 
-func (c Client) Update(ctx context.Context) error {
-	_, err := c.client.Update(ctx, nil)
+func (c Client) Update(ctx context.Context, triggeredBy string) (core.Job, error) {
+	job, err := c.client.Update(ctx, &updatepb.UpdateRequest{TriggeredBy: triggeredBy})
+	if err != nil {
+		return fromJobStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) GetJob(ctx context.Context, id int64) (core.Job, error) {
+	job, err := c.client.GetJob(ctx, &updatepb.JobRequest{Id: id})
+	if err != nil {
+		return core.Job{}, fromStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) ListJobs(ctx context.Context, limit, offset int) ([]core.Job, error) {
+	reply, err := c.client.ListJobs(ctx, &updatepb.ListJobsRequest{Limit: int64(limit), Offset: int64(offset)})
+	if err != nil {
+		return nil, fromStatusError(err)
+	}
+	jobs := make([]core.Job, len(reply.Jobs))
+	for i, job := range reply.Jobs {
+		jobs[i] = fromJob(job)
+	}
+	return jobs, nil
+}
+
+func (c Client) CancelJob(ctx context.Context, id int64) error {
+	_, err := c.client.CancelJob(ctx, &updatepb.JobRequest{Id: id})
+	return fromStatusError(err)
+}
+
+func (c Client) RetryFailed(ctx context.Context, triggeredBy string) (core.Job, error) {
+	job, err := c.client.RetryFailed(ctx, &updatepb.UpdateRequest{TriggeredBy: triggeredBy})
+	if err != nil {
+		return fromJobStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) Reprocess(ctx context.Context, triggeredBy string) (core.Job, error) {
+	job, err := c.client.Reprocess(ctx, &updatepb.UpdateRequest{TriggeredBy: triggeredBy})
+	if err != nil {
+		return fromJobStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) Refetch(ctx context.Context, triggeredBy string, from, to int) (core.Job, error) {
+	job, err := c.client.Refetch(ctx, &updatepb.RefetchRequest{
+		From:        int64(from),
+		To:          int64(to),
+		TriggeredBy: triggeredBy,
+	})
+	if err != nil {
+		return fromJobStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) Refresh(ctx context.Context, triggeredBy string, filter core.RefreshFilter) (core.Job, error) {
+	job, err := c.client.Refresh(ctx, &updatepb.RefreshRequest{
+		TriggeredBy:     triggeredBy,
+		Days:            int64(filter.Days),
+		EmptyTranscript: filter.EmptyTranscript,
+	})
+	if err != nil {
+		return fromJobStatusError(err)
+	}
+	return fromJob(job), nil
+}
+
+func (c Client) Delete(ctx context.Context, from, to int) (int, error) {
+	reply, err := c.client.Delete(ctx, &updatepb.DeleteRequest{From: int64(from), To: int64(to)})
+	if err != nil {
+		return 0, fromStatusError(err)
+	}
+	return int(reply.Deleted), nil
+}
+
+func (c Client) ListFailed(ctx context.Context) ([]core.FailedComics, error) {
+	reply, err := c.client.ListFailed(ctx, nil)
 	if err != nil {
-		if st, ok := status.FromError(err); ok {
-			return errors.New(st.Message())
+		return nil, err
+	}
+	failed := make([]core.FailedComics, len(reply.Comics))
+	for i, f := range reply.Comics {
+		failed[i] = core.FailedComics{
+			ID:         int(f.Id),
+			Stage:      f.Stage,
+			ErrorClass: f.ErrorClass,
+			Error:      f.Error,
+			Attempts:   int(f.Attempts),
+		}
+		if f.FailedAt != nil {
+			failed[i].FailedAt = f.FailedAt.AsTime()
+		}
+	}
+	return failed, nil
+}
+
+func (c Client) ListQuarantined(ctx context.Context) ([]core.QuarantinedComics, error) {
+	reply, err := c.client.ListQuarantined(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	quarantined := make([]core.QuarantinedComics, len(reply.Comics))
+	for i, q := range reply.Comics {
+		quarantined[i] = core.QuarantinedComics{
+			ID:      int(q.Id),
+			Payload: q.Payload,
+			Reason:  q.Reason,
 		}
+		if q.QuarantinedAt != nil {
+			quarantined[i].QuarantinedAt = q.QuarantinedAt.AsTime()
+		}
+	}
+	return quarantined, nil
+}
+
+func fromTimings(timings *updatepb.StageTimings) core.StageTimings {
+	return core.StageTimings{
+		Fetch:     timings.GetFetch().AsDuration(),
+		Normalize: timings.GetNormalize().AsDuration(),
+		Store:     timings.GetStore().AsDuration(),
+	}
+}
+
+func fromJob(job *updatepb.Job) core.Job {
+	result := core.Job{
+		ID:          job.Id,
+		Kind:        job.Kind,
+		Total:       int(job.Total),
+		Fetched:     int(job.Fetched),
+		Normalized:  int(job.Normalized),
+		Stored:      int(job.Stored),
+		Failed:      int(job.Failed),
+		Changed:     int(job.Changed),
+		Timings:     fromTimings(job.Timings),
+		Error:       job.Error,
+		TriggeredBy: job.TriggeredBy,
+	}
+	switch job.Outcome {
+	case updatepb.JobOutcome_JOB_OUTCOME_RUNNING:
+		result.Outcome = core.JobRunning
+	case updatepb.JobOutcome_JOB_OUTCOME_SUCCEEDED:
+		result.Outcome = core.JobSucceeded
+	case updatepb.JobOutcome_JOB_OUTCOME_FAILED:
+		result.Outcome = core.JobFailed
+	case updatepb.JobOutcome_JOB_OUTCOME_CANCELED:
+		result.Outcome = core.JobCanceled
+	}
+	if job.StartedAt != nil {
+		result.StartedAt = job.StartedAt.AsTime()
+	}
+	if job.FinishedAt != nil {
+		result.FinishedAt = job.FinishedAt.AsTime()
+	}
+	return result
+}
+
+func fromStatusError(err error) error {
+	switch status.Code(err) {
+	case codes.AlreadyExists:
+		return core.ErrAlreadyExists
+	case codes.NotFound:
+		return core.ErrNotFound
+	case codes.InvalidArgument:
+		return core.ErrBadArguments
+	case codes.FailedPrecondition:
+		return core.ErrJobFinished
 	}
 	return err
 }
 
+// fromJobStatusError converts the error of starting a job and returns the
+// running job, if the update service has sent it along with AlreadyExists.
+func fromJobStatusError(err error) (core.Job, error) {
+	for _, detail := range status.Convert(err).Details() {
+		if job, ok := detail.(*updatepb.Job); ok {
+			return fromJob(job), fromStatusError(err)
+		}
+	}
+	return core.Job{}, fromStatusError(err)
+}
+
 func (c Client) Drop(ctx context.Context) error {
-	_, err := c.client.Drop(ctx, nil)
-	return err
+	if _, err := c.client.Drop(ctx, nil); err != nil {
+		return fromStatusError(err)
+	}
+	return nil
+}
+
+func (c Client) Restore(ctx context.Context) (int, error) {
+	reply, err := c.client.Restore(ctx, nil)
+	if err != nil {
+		return 0, fromStatusError(err)
+	}
+	return int(reply.Restored), nil
+}
+
+func (c Client) WatchUpdate(ctx context.Context) (<-chan core.UpdateProgress, error) {
+	stream, err := c.client.WatchUpdate(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan core.UpdateProgress)
+	go func() {
+		defer close(ch)
+		for {
+			progress, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+					c.log.Error("update progress stream failed", "error", err)
+				}
+				return
+			}
+			select {
+			case ch <- core.UpdateProgress{
+				Status:     fromStatus(progress.Status),
+				Total:      int(progress.Total),
+				Fetched:    int(progress.Fetched),
+				Normalized: int(progress.Normalized),
+				Stored:     int(progress.Stored),
+				Failed:     int(progress.Failed),
+				Changed:    int(progress.Changed),
+				CurrentID:  int(progress.CurrentId),
+				Timings:    fromTimings(progress.Timings),
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
 }
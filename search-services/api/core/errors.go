@@ -7,3 +7,5 @@ var ErrAlreadyExists = errors.New("resource or task already exists")
 var ErrNotFound = errors.New("resource is not found")
 var ErrUnauthorized = errors.New("user is unauthorized")
 var ErrStarting = errors.New("error trying to start")
+var ErrJobFinished = errors.New("job is already finished")
+var ErrNotReady = errors.New("service is not ready")
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type UpdateStatus string
 
 const (
@@ -8,6 +10,68 @@ const (
 	StatusUpdateRunning UpdateStatus = "running"
 )
 
+type UpdateState struct {
+	Status  UpdateStatus
+	LastRun time.Time
+	NextRun time.Time
+	// Holder is the update replica running a job, empty if no replica does.
+	Holder    string
+	HeldSince time.Time
+}
+
+type UpdateProgress struct {
+	Status     UpdateStatus
+	Total      int
+	Fetched    int
+	Normalized int
+	Stored     int
+	Failed     int
+	Changed    int
+	CurrentID  int
+	Timings    StageTimings
+}
+
+// StageTimings is the time spent by workers of every stage of an update job,
+// summed over workers.
+type StageTimings struct {
+	Fetch     time.Duration
+	Normalize time.Duration
+	Store     time.Duration
+}
+
+type JobOutcome string
+
+const (
+	JobRunning   JobOutcome = "running"
+	JobSucceeded JobOutcome = "succeeded"
+	JobFailed    JobOutcome = "failed"
+	JobCanceled  JobOutcome = "canceled"
+)
+
+type Job struct {
+	ID          int64
+	Kind        string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Outcome     JobOutcome
+	Total       int
+	Fetched     int
+	Normalized  int
+	Stored      int
+	Failed      int
+	Changed     int
+	Timings     StageTimings
+	Error       string
+	TriggeredBy string
+}
+
+// RefreshFilter narrows comics checked for upstream changes, see
+// POST /api/db/update?mode=refresh.
+type RefreshFilter struct {
+	Days            int
+	EmptyTranscript bool
+}
+
 const (
 	StatusAccepted = "accepted"
 	StatusRejected = "rejected"
@@ -18,9 +82,51 @@ type UpdateStats struct {
 	WordsUnique   int
 	ComicsFetched int
 	ComicsTotal   int
+	ComicsFailed  int
+}
+
+type FailedComics struct {
+	ID         int
+	Stage      string
+	ErrorClass string
+	Error      string
+	Attempts   int
+	FailedAt   time.Time
+}
+
+type QuarantinedComics struct {
+	ID            int
+	Payload       string
+	Reason        string
+	QuarantinedAt time.Time
 }
 
 type Comics struct {
-	ID  int
-	URL string
+	ID         int
+	URL        string
+	Title      string
+	SafeTitle  string
+	Alt        string
+	Transcript string
+	Published  time.Time
+	// Score, Matched and Missing are set for search results only. Matched and
+	// Missing list normalized words of the phrase found and not found in
+	// comics.
+	Score   float64
+	Matched []string
+	Missing []string
+}
+
+// IndexStats describes the index serving index search, Generation grows with
+// every rebuild of the index.
+type IndexStats struct {
+	Generation uint64
+	BuiltAt    time.Time
+	Words      int
+	Comics     int
+}
+
+type ComicsPage struct {
+	Comics []Comics
+	Total  int
 }
@@ -11,15 +11,30 @@ type Pinger interface {
 }
 
 type Updater interface {
-	Update(context.Context) error
+	Update(ctx context.Context, triggeredBy string) (Job, error)
 	Stats(context.Context) (UpdateStats, error)
-	Status(context.Context) (UpdateStatus, error)
+	Status(context.Context) (UpdateState, error)
 	Drop(context.Context) error
+	Restore(context.Context) (int, error)
+	WatchUpdate(context.Context) (<-chan UpdateProgress, error)
+	GetJob(ctx context.Context, id int64) (Job, error)
+	ListJobs(ctx context.Context, limit, offset int) ([]Job, error)
+	CancelJob(ctx context.Context, id int64) error
+	RetryFailed(ctx context.Context, triggeredBy string) (Job, error)
+	Reprocess(ctx context.Context, triggeredBy string) (Job, error)
+	Refetch(ctx context.Context, triggeredBy string, from, to int) (Job, error)
+	Refresh(ctx context.Context, triggeredBy string, filter RefreshFilter) (Job, error)
+	Delete(ctx context.Context, from, to int) (int, error)
+	ListFailed(context.Context) ([]FailedComics, error)
+	ListQuarantined(context.Context) ([]QuarantinedComics, error)
 }
 
 type Searcher interface {
 	Search(context.Context, string, int) ([]Comics, error)
 	SearchIndex(context.Context, string, int) ([]Comics, error)
+	Comics(context.Context, int) (Comics, error)
+	List(ctx context.Context, offset, limit int, order string) (ComicsPage, error)
+	IndexStats(context.Context) (IndexStats, error)
 }
 
 type Loginer interface {
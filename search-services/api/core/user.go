@@ -0,0 +1,16 @@
+package core
+
+import "context"
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user name.
+func WithUser(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, userKey{}, name)
+}
+
+// UserFromContext returns the user name stored by WithUser, if any.
+func UserFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(userKey{}).(string)
+	return name, ok
+}
@@ -88,11 +88,27 @@ func main() {
 	setHandler(mux, "POST /api/db/update", rest.NewUpdateHandler(log, updateClient), auth)
 	mux.Handle("GET /api/db/stats", rest.NewUpdateStatsHandler(log, updateClient))
 	mux.Handle("GET /api/db/status", rest.NewUpdateStatusHandler(log, updateClient))
+	mux.Handle("GET /api/db/update/events", rest.NewUpdateEventsHandler(log, updateClient))
+	mux.Handle("GET /api/db/jobs", rest.NewJobsHandler(log, updateClient))
+	mux.Handle("GET /api/db/jobs/{id}", rest.NewJobHandler(log, updateClient))
+	setHandler(mux, "POST /api/db/jobs/{id}/cancel", rest.NewCancelJobHandler(log, updateClient), auth)
+	mux.Handle("GET /api/db/failed", rest.NewFailedHandler(log, updateClient))
+	setHandler(mux, "POST /api/db/retry", rest.NewRetryFailedHandler(log, updateClient), auth)
+	setHandler(mux, "POST /api/db/reprocess", rest.NewReprocessHandler(log, updateClient), auth)
+	setHandler(mux, "POST /api/db/comics/refetch", rest.NewRefetchHandler(log, updateClient), auth)
+	setHandler(mux, "POST /api/db/comics/{id}/refetch", rest.NewRefetchHandler(log, updateClient), auth)
+	setHandler(mux, "DELETE /api/db/comics", rest.NewDeleteHandler(log, updateClient), auth)
+	setHandler(mux, "DELETE /api/db/comics/{id}", rest.NewDeleteHandler(log, updateClient), auth)
+	setHandler(mux, "GET /api/db/quarantine", rest.NewQuarantineHandler(log, updateClient), auth)
 	setHandler(mux, "DELETE /api/db", rest.NewDropHandler(log, updateClient), auth)
+	setHandler(mux, "POST /api/db/restore", rest.NewRestoreHandler(log, updateClient), auth)
 	mux.Handle("GET /api/search",
 		middleware.Concurrency(rest.NewSearchHandler(log, searchClient), concurrencyLimiter))
 	mux.Handle("GET /api/isearch",
 		middleware.Rate(rest.NewSearchIndexHandler(log, searchClient), rateLimiter))
+	mux.Handle("GET /api/isearch/stats", rest.NewIndexStatsHandler(log, searchClient))
+	mux.Handle("GET /api/comics", rest.NewComicsListHandler(log, searchClient))
+	mux.Handle("GET /api/comics/{id}", rest.NewComicsHandler(log, searchClient))
 	mux.Handle("POST /api/login", rest.NewLoginHandler(log, auth))
 
 	server := http.Server{
@@ -0,0 +1,80 @@
+// Package events describes messages the services exchange through the
+// broker. Messages are JSON encoded and versioned, so subscribers can tell
+// what has changed and skip messages of schemas they do not know.
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SubjectDBChanged is the subject DBChanged events are published on.
+const SubjectDBChanged = "xkcd.db.updated"
+
+// SchemaVersion is the version of DBChanged written by Encode. It is bumped
+// on incompatible changes of the schema.
+const SchemaVersion = 1
+
+type Type string
+
+const (
+	// TypeComicsStored is sent when a job has stored comics, Added and Updated
+	// hold their ids.
+	TypeComicsStored Type = "comics.stored"
+	// TypeComicsDeleted is sent when comics have been deleted, Deleted holds
+	// their ids.
+	TypeComicsDeleted Type = "comics.deleted"
+	// TypeDBDropped is sent when every comics has been removed.
+	TypeDBDropped Type = "db.dropped"
+	// TypeDBRestored is sent when dropped comics have been restored.
+	TypeDBRestored Type = "db.restored"
+	// TypeLegacy marks plain text messages of publishers that predate the
+	// schema. They tell only that something has changed.
+	TypeLegacy Type = "legacy"
+)
+
+// DBChanged tells subscribers that comics in db have changed.
+type DBChanged struct {
+	Version int       `json:"version"`
+	Type    Type      `json:"type"`
+	JobID   int64     `json:"job_id,omitempty"`
+	Added   []int     `json:"added,omitempty"`
+	Updated []int     `json:"updated,omitempty"`
+	Deleted []int     `json:"deleted,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Partial reports whether the event lists every changed comics, otherwise
+// subscribers have to reload all comics.
+func (e DBChanged) Partial() bool {
+	return e.Type == TypeComicsStored || e.Type == TypeComicsDeleted
+}
+
+// Encode marshals the event with the current schema version, zero Time is
+// replaced with the current time.
+func Encode(e DBChanged) ([]byte, error) {
+	e.Version = SchemaVersion
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	return json.Marshal(e)
+}
+
+// Decode unmarshals the event. Messages that are not JSON objects are
+// decoded as TypeLegacy events. Events of a newer schema version are
+// returned together with an error.
+func Decode(data []byte) (DBChanged, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return DBChanged{Type: TypeLegacy}, nil
+	}
+	var e DBChanged
+	if err := json.Unmarshal(data, &e); err != nil {
+		return DBChanged{}, fmt.Errorf("wrong db changed event: %w", err)
+	}
+	if e.Version > SchemaVersion {
+		return e, fmt.Errorf("unsupported db changed event version %d", e.Version)
+	}
+	return e, nil
+}
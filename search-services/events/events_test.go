@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	data, err := Encode(DBChanged{Type: TypeComicsStored, JobID: 7, Added: []int{3}, Updated: []int{1, 2}, Time: at})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"type":"comics.stored","job_id":7,"added":[3],"updated":[1,2],
+		"time":"2025-01-01T10:00:00Z"}`, string(data))
+
+	e, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, DBChanged{
+		Version: SchemaVersion, Type: TypeComicsStored, JobID: 7, Added: []int{3}, Updated: []int{1, 2}, Time: at,
+	}, e)
+	assert.True(t, e.Partial())
+}
+
+func TestEncode_SetsTime(t *testing.T) {
+	data, err := Encode(DBChanged{Type: TypeDBDropped})
+	require.NoError(t, err)
+	e, err := Decode(data)
+	require.NoError(t, err)
+	assert.False(t, e.Time.IsZero())
+	assert.False(t, e.Partial())
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    DBChanged
+		wantErr bool
+	}{
+		{name: "legacy", data: "XKCD DB has been updated", want: DBChanged{Type: TypeLegacy}},
+		{name: "empty", data: "", want: DBChanged{Type: TypeLegacy}},
+		{name: "broken json", data: `{"version": 1,`, wantErr: true},
+		{
+			name:    "newer version",
+			data:    `{"version": 2, "type": "db.dropped"}`,
+			want:    DBChanged{Version: 2, Type: TypeDBDropped},
+			wantErr: true,
+		},
+		{
+			name: "deleted",
+			data: ` {"version": 1, "type": "comics.deleted", "deleted": [5, 6]}`,
+			want: DBChanged{Version: 1, Type: TypeComicsDeleted, Deleted: []int{5, 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Decode([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, e)
+		})
+	}
+}
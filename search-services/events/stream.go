@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// EnsureStream creates the JetStream stream keeping DBChanged events for
+// maxAge or updates the existing one. Both the publisher and subscribers
+// call it, so events are kept whichever of them starts first.
+func EnsureStream(ctx context.Context, js jetstream.JetStream, name string, maxAge time.Duration) (jetstream.Stream, error) {
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     name,
+		Subjects: []string{SubjectDBChanged},
+		Storage:  jetstream.FileStorage,
+		MaxAge:   maxAge,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream %q: %w", name, err)
+	}
+	return stream, nil
+}
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,6 +23,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Order int32
+
+const (
+	Order_ORDER_UNSPECIFIED Order = 0
+	Order_ORDER_ASC         Order = 1
+	Order_ORDER_DESC        Order = 2
+)
+
+// Enum value maps for Order.
+var (
+	Order_name = map[int32]string{
+		0: "ORDER_UNSPECIFIED",
+		1: "ORDER_ASC",
+		2: "ORDER_DESC",
+	}
+	Order_value = map[string]int32{
+		"ORDER_UNSPECIFIED": 0,
+		"ORDER_ASC":         1,
+		"ORDER_DESC":        2,
+	}
+)
+
+func (x Order) Enum() *Order {
+	p := new(Order)
+	*p = x
+	return p
+}
+
+func (x Order) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Order) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_search_search_proto_enumTypes[0].Descriptor()
+}
+
+func (Order) Type() protoreflect.EnumType {
+	return &file_proto_search_search_proto_enumTypes[0]
+}
+
+func (x Order) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Order.Descriptor instead.
+func (Order) EnumDescriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{0}
+}
+
 type ComicsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -75,9 +125,21 @@ func (x *ComicsRequest) GetWords() string {
 }
 
 type Comics struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url        string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title      string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	SafeTitle  string                 `protobuf:"bytes,4,opt,name=safe_title,json=safeTitle,proto3" json:"safe_title,omitempty"`
+	Alt        string                 `protobuf:"bytes,5,opt,name=alt,proto3" json:"alt,omitempty"`
+	Transcript string                 `protobuf:"bytes,6,opt,name=transcript,proto3" json:"transcript,omitempty"`
+	Published  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=published,proto3" json:"published,omitempty"`
+	// score, matched and missing are set for search results only. Scores of
+	// Search count matched words, scores of SearchIndex are BM25 scores.
+	Score float64 `protobuf:"fixed64,8,opt,name=score,proto3" json:"score,omitempty"`
+	// normalized words of the phrase found in comics
+	Matched []string `protobuf:"bytes,9,rep,name=matched,proto3" json:"matched,omitempty"`
+	// normalized words of the phrase not found in comics
+	Missing       []string `protobuf:"bytes,10,rep,name=missing,proto3" json:"missing,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -126,6 +188,62 @@ func (x *Comics) GetUrl() string {
 	return ""
 }
 
+func (x *Comics) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Comics) GetSafeTitle() string {
+	if x != nil {
+		return x.SafeTitle
+	}
+	return ""
+}
+
+func (x *Comics) GetAlt() string {
+	if x != nil {
+		return x.Alt
+	}
+	return ""
+}
+
+func (x *Comics) GetTranscript() string {
+	if x != nil {
+		return x.Transcript
+	}
+	return ""
+}
+
+func (x *Comics) GetPublished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+func (x *Comics) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Comics) GetMatched() []string {
+	if x != nil {
+		return x.Matched
+	}
+	return nil
+}
+
+func (x *Comics) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
 type ComicsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Comics        []*Comics              `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
@@ -170,23 +288,287 @@ func (x *ComicsResponse) GetComics() []*Comics {
 	return nil
 }
 
+type ComicsIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicsIdRequest) Reset() {
+	*x = ComicsIdRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicsIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicsIdRequest) ProtoMessage() {}
+
+func (x *ComicsIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicsIdRequest.ProtoReflect.Descriptor instead.
+func (*ComicsIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ComicsIdRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListComicsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        int64                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Order         Order                  `protobuf:"varint,3,opt,name=order,proto3,enum=search.Order" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComicsRequest) Reset() {
+	*x = ListComicsRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComicsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComicsRequest) ProtoMessage() {}
+
+func (x *ListComicsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComicsRequest.ProtoReflect.Descriptor instead.
+func (*ListComicsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListComicsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListComicsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListComicsRequest) GetOrder() Order {
+	if x != nil {
+		return x.Order
+	}
+	return Order_ORDER_UNSPECIFIED
+}
+
+type ListComicsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comics        []*Comics              `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComicsResponse) Reset() {
+	*x = ListComicsResponse{}
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComicsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComicsResponse) ProtoMessage() {}
+
+func (x *ListComicsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComicsResponse.ProtoReflect.Descriptor instead.
+func (*ListComicsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListComicsResponse) GetComics() []*Comics {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+func (x *ListComicsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// IndexStatsReply describes the index serving SearchIndex. Generation counts
+// builds of the index since start of the service, the first one is 1.
+type IndexStatsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generation    uint64                 `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	BuiltAt       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=built_at,json=builtAt,proto3" json:"built_at,omitempty"`
+	Words         int64                  `protobuf:"varint,3,opt,name=words,proto3" json:"words,omitempty"`
+	Comics        int64                  `protobuf:"varint,4,opt,name=comics,proto3" json:"comics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexStatsReply) Reset() {
+	*x = IndexStatsReply{}
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexStatsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexStatsReply) ProtoMessage() {}
+
+func (x *IndexStatsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexStatsReply.ProtoReflect.Descriptor instead.
+func (*IndexStatsReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IndexStatsReply) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetBuiltAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BuiltAt
+	}
+	return nil
+}
+
+func (x *IndexStatsReply) GetWords() int64 {
+	if x != nil {
+		return x.Words
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetComics() int64 {
+	if x != nil {
+		return x.Comics
+	}
+	return 0
+}
+
 var File_proto_search_search_proto protoreflect.FileDescriptor
 
 const file_proto_search_search_proto_rawDesc = "" +
 	"\n" +
-	"\x19proto/search/search.proto\x12\x06search\x1a\x1bgoogle/protobuf/empty.proto\";\n" +
+	"\x19proto/search/search.proto\x12\x06search\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\";\n" +
 	"\rComicsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x14\n" +
-	"\x05words\x18\x02 \x01(\tR\x05words\"*\n" +
+	"\x05words\x18\x02 \x01(\tR\x05words\"\x95\x02\n" +
 	"\x06Comics\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url\"8\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"safe_title\x18\x04 \x01(\tR\tsafeTitle\x12\x10\n" +
+	"\x03alt\x18\x05 \x01(\tR\x03alt\x12\x1e\n" +
+	"\n" +
+	"transcript\x18\x06 \x01(\tR\n" +
+	"transcript\x128\n" +
+	"\tpublished\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tpublished\x12\x14\n" +
+	"\x05score\x18\b \x01(\x01R\x05score\x12\x18\n" +
+	"\amatched\x18\t \x03(\tR\amatched\x12\x18\n" +
+	"\amissing\x18\n" +
+	" \x03(\tR\amissing\"8\n" +
 	"\x0eComicsResponse\x12&\n" +
-	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics2\xb9\x01\n" +
+	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics\"!\n" +
+	"\x0fComicsIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"f\n" +
+	"\x11ListComicsRequest\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12#\n" +
+	"\x05order\x18\x03 \x01(\x0e2\r.search.OrderR\x05order\"R\n" +
+	"\x12ListComicsResponse\x12&\n" +
+	"\x06comics\x18\x01 \x03(\v2\x0e.search.ComicsR\x06comics\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\x96\x01\n" +
+	"\x0fIndexStatsReply\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x04R\n" +
+	"generation\x125\n" +
+	"\bbuilt_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\abuiltAt\x12\x14\n" +
+	"\x05words\x18\x03 \x01(\x03R\x05words\x12\x16\n" +
+	"\x06comics\x18\x04 \x01(\x03R\x06comics*=\n" +
+	"\x05Order\x12\x15\n" +
+	"\x11ORDER_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tORDER_ASC\x10\x01\x12\x0e\n" +
+	"\n" +
+	"ORDER_DESC\x10\x022\xf3\x02\n" +
 	"\x06Search\x128\n" +
 	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x127\n" +
 	"\x06Search\x12\x15.search.ComicsRequest\x1a\x16.search.ComicsResponse\x12<\n" +
-	"\vSearchIndex\x12\x15.search.ComicsRequest\x1a\x16.search.ComicsResponseB\x1fZ\x1dyadro.com/course/proto/searchb\x06proto3"
+	"\vSearchIndex\x12\x15.search.ComicsRequest\x1a\x16.search.ComicsResponse\x124\n" +
+	"\tGetComics\x12\x17.search.ComicsIdRequest\x1a\x0e.search.Comics\x12C\n" +
+	"\n" +
+	"ListComics\x12\x19.search.ListComicsRequest\x1a\x1a.search.ListComicsResponse\x12=\n" +
+	"\n" +
+	"IndexStats\x12\x16.google.protobuf.Empty\x1a\x17.search.IndexStatsReplyB\x1fZ\x1dyadro.com/course/proto/searchb\x06proto3"
 
 var (
 	file_proto_search_search_proto_rawDescOnce sync.Once
@@ -200,26 +582,43 @@ func file_proto_search_search_proto_rawDescGZIP() []byte {
 	return file_proto_search_search_proto_rawDescData
 }
 
-var file_proto_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_search_search_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_search_search_proto_goTypes = []any{
-	(*ComicsRequest)(nil),  // 0: search.ComicsRequest
-	(*Comics)(nil),         // 1: search.Comics
-	(*ComicsResponse)(nil), // 2: search.ComicsResponse
-	(*emptypb.Empty)(nil),  // 3: google.protobuf.Empty
+	(Order)(0),                    // 0: search.Order
+	(*ComicsRequest)(nil),         // 1: search.ComicsRequest
+	(*Comics)(nil),                // 2: search.Comics
+	(*ComicsResponse)(nil),        // 3: search.ComicsResponse
+	(*ComicsIdRequest)(nil),       // 4: search.ComicsIdRequest
+	(*ListComicsRequest)(nil),     // 5: search.ListComicsRequest
+	(*ListComicsResponse)(nil),    // 6: search.ListComicsResponse
+	(*IndexStatsReply)(nil),       // 7: search.IndexStatsReply
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 9: google.protobuf.Empty
 }
 var file_proto_search_search_proto_depIdxs = []int32{
-	1, // 0: search.ComicsResponse.comics:type_name -> search.Comics
-	3, // 1: search.Search.Ping:input_type -> google.protobuf.Empty
-	0, // 2: search.Search.Search:input_type -> search.ComicsRequest
-	0, // 3: search.Search.SearchIndex:input_type -> search.ComicsRequest
-	3, // 4: search.Search.Ping:output_type -> google.protobuf.Empty
-	2, // 5: search.Search.Search:output_type -> search.ComicsResponse
-	2, // 6: search.Search.SearchIndex:output_type -> search.ComicsResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	8,  // 0: search.Comics.published:type_name -> google.protobuf.Timestamp
+	2,  // 1: search.ComicsResponse.comics:type_name -> search.Comics
+	0,  // 2: search.ListComicsRequest.order:type_name -> search.Order
+	2,  // 3: search.ListComicsResponse.comics:type_name -> search.Comics
+	8,  // 4: search.IndexStatsReply.built_at:type_name -> google.protobuf.Timestamp
+	9,  // 5: search.Search.Ping:input_type -> google.protobuf.Empty
+	1,  // 6: search.Search.Search:input_type -> search.ComicsRequest
+	1,  // 7: search.Search.SearchIndex:input_type -> search.ComicsRequest
+	4,  // 8: search.Search.GetComics:input_type -> search.ComicsIdRequest
+	5,  // 9: search.Search.ListComics:input_type -> search.ListComicsRequest
+	9,  // 10: search.Search.IndexStats:input_type -> google.protobuf.Empty
+	9,  // 11: search.Search.Ping:output_type -> google.protobuf.Empty
+	3,  // 12: search.Search.Search:output_type -> search.ComicsResponse
+	3,  // 13: search.Search.SearchIndex:output_type -> search.ComicsResponse
+	2,  // 14: search.Search.GetComics:output_type -> search.Comics
+	6,  // 15: search.Search.ListComics:output_type -> search.ListComicsResponse
+	7,  // 16: search.Search.IndexStats:output_type -> search.IndexStatsReply
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_search_search_proto_init() }
@@ -232,13 +631,14 @@ func file_proto_search_search_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_search_search_proto_rawDesc), len(file_proto_search_search_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_search_search_proto_goTypes,
 		DependencyIndexes: file_proto_search_search_proto_depIdxs,
+		EnumInfos:         file_proto_search_search_proto_enumTypes,
 		MessageInfos:      file_proto_search_search_proto_msgTypes,
 	}.Build()
 	File_proto_search_search_proto = out.File
@@ -23,6 +23,9 @@ const (
 	Search_Ping_FullMethodName        = "/search.Search/Ping"
 	Search_Search_FullMethodName      = "/search.Search/Search"
 	Search_SearchIndex_FullMethodName = "/search.Search/SearchIndex"
+	Search_GetComics_FullMethodName   = "/search.Search/GetComics"
+	Search_ListComics_FullMethodName  = "/search.Search/ListComics"
+	Search_IndexStats_FullMethodName  = "/search.Search/IndexStats"
 )
 
 // SearchClient is the client API for Search service.
@@ -32,6 +35,9 @@ type SearchClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Search(ctx context.Context, in *ComicsRequest, opts ...grpc.CallOption) (*ComicsResponse, error)
 	SearchIndex(ctx context.Context, in *ComicsRequest, opts ...grpc.CallOption) (*ComicsResponse, error)
+	GetComics(ctx context.Context, in *ComicsIdRequest, opts ...grpc.CallOption) (*Comics, error)
+	ListComics(ctx context.Context, in *ListComicsRequest, opts ...grpc.CallOption) (*ListComicsResponse, error)
+	IndexStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IndexStatsReply, error)
 }
 
 type searchClient struct {
@@ -72,6 +78,36 @@ func (c *searchClient) SearchIndex(ctx context.Context, in *ComicsRequest, opts
 	return out, nil
 }
 
+func (c *searchClient) GetComics(ctx context.Context, in *ComicsIdRequest, opts ...grpc.CallOption) (*Comics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Comics)
+	err := c.cc.Invoke(ctx, Search_GetComics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) ListComics(ctx context.Context, in *ListComicsRequest, opts ...grpc.CallOption) (*ListComicsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListComicsResponse)
+	err := c.cc.Invoke(ctx, Search_ListComics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) IndexStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IndexStatsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexStatsReply)
+	err := c.cc.Invoke(ctx, Search_IndexStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SearchServer is the server API for Search service.
 // All implementations must embed UnimplementedSearchServer
 // for forward compatibility.
@@ -79,6 +115,9 @@ type SearchServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Search(context.Context, *ComicsRequest) (*ComicsResponse, error)
 	SearchIndex(context.Context, *ComicsRequest) (*ComicsResponse, error)
+	GetComics(context.Context, *ComicsIdRequest) (*Comics, error)
+	ListComics(context.Context, *ListComicsRequest) (*ListComicsResponse, error)
+	IndexStats(context.Context, *emptypb.Empty) (*IndexStatsReply, error)
 	mustEmbedUnimplementedSearchServer()
 }
 
@@ -98,6 +137,15 @@ func (UnimplementedSearchServer) Search(context.Context, *ComicsRequest) (*Comic
 func (UnimplementedSearchServer) SearchIndex(context.Context, *ComicsRequest) (*ComicsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchIndex not implemented")
 }
+func (UnimplementedSearchServer) GetComics(context.Context, *ComicsIdRequest) (*Comics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComics not implemented")
+}
+func (UnimplementedSearchServer) ListComics(context.Context, *ListComicsRequest) (*ListComicsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComics not implemented")
+}
+func (UnimplementedSearchServer) IndexStats(context.Context, *emptypb.Empty) (*IndexStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IndexStats not implemented")
+}
 func (UnimplementedSearchServer) mustEmbedUnimplementedSearchServer() {}
 func (UnimplementedSearchServer) testEmbeddedByValue()                {}
 
@@ -173,6 +221,60 @@ func _Search_SearchIndex_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Search_GetComics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComicsIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).GetComics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_GetComics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).GetComics(ctx, req.(*ComicsIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_ListComics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).ListComics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_ListComics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).ListComics(ctx, req.(*ListComicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_IndexStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).IndexStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_IndexStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).IndexStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Search_ServiceDesc is the grpc.ServiceDesc for Search service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -192,6 +294,18 @@ var Search_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchIndex",
 			Handler:    _Search_SearchIndex_Handler,
 		},
+		{
+			MethodName: "GetComics",
+			Handler:    _Search_GetComics_Handler,
+		},
+		{
+			MethodName: "ListComics",
+			Handler:    _Search_ListComics_Handler,
+		},
+		{
+			MethodName: "IndexStats",
+			Handler:    _Search_IndexStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/search/search.proto",
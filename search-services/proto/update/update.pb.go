@@ -9,7 +9,9 @@ package update
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -71,12 +73,68 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_proto_update_update_proto_rawDescGZIP(), []int{0}
 }
 
+type JobOutcome int32
+
+const (
+	JobOutcome_JOB_OUTCOME_UNSPECIFIED JobOutcome = 0
+	JobOutcome_JOB_OUTCOME_RUNNING     JobOutcome = 1
+	JobOutcome_JOB_OUTCOME_SUCCEEDED   JobOutcome = 2
+	JobOutcome_JOB_OUTCOME_FAILED      JobOutcome = 3
+	JobOutcome_JOB_OUTCOME_CANCELED    JobOutcome = 4
+)
+
+// Enum value maps for JobOutcome.
+var (
+	JobOutcome_name = map[int32]string{
+		0: "JOB_OUTCOME_UNSPECIFIED",
+		1: "JOB_OUTCOME_RUNNING",
+		2: "JOB_OUTCOME_SUCCEEDED",
+		3: "JOB_OUTCOME_FAILED",
+		4: "JOB_OUTCOME_CANCELED",
+	}
+	JobOutcome_value = map[string]int32{
+		"JOB_OUTCOME_UNSPECIFIED": 0,
+		"JOB_OUTCOME_RUNNING":     1,
+		"JOB_OUTCOME_SUCCEEDED":   2,
+		"JOB_OUTCOME_FAILED":      3,
+		"JOB_OUTCOME_CANCELED":    4,
+	}
+)
+
+func (x JobOutcome) Enum() *JobOutcome {
+	p := new(JobOutcome)
+	*p = x
+	return p
+}
+
+func (x JobOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_update_update_proto_enumTypes[1].Descriptor()
+}
+
+func (JobOutcome) Type() protoreflect.EnumType {
+	return &file_proto_update_update_proto_enumTypes[1]
+}
+
+func (x JobOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobOutcome.Descriptor instead.
+func (JobOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{1}
+}
+
 type StatsReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WordsTotal    int64                  `protobuf:"varint,1,opt,name=words_total,json=wordsTotal,proto3" json:"words_total,omitempty"`
 	WordsUnique   int64                  `protobuf:"varint,2,opt,name=words_unique,json=wordsUnique,proto3" json:"words_unique,omitempty"`
 	ComicsTotal   int64                  `protobuf:"varint,3,opt,name=comics_total,json=comicsTotal,proto3" json:"comics_total,omitempty"`
 	ComicsFetched int64                  `protobuf:"varint,4,opt,name=comics_fetched,json=comicsFetched,proto3" json:"comics_fetched,omitempty"`
+	ComicsFailed  int64                  `protobuf:"varint,5,opt,name=comics_failed,json=comicsFailed,proto3" json:"comics_failed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -139,9 +197,21 @@ func (x *StatsReply) GetComicsFetched() int64 {
 	return 0
 }
 
+func (x *StatsReply) GetComicsFailed() int64 {
+	if x != nil {
+		return x.ComicsFailed
+	}
+	return 0
+}
+
 type StatusReply struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=update.Status" json:"status,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Status  Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=update.Status" json:"status,omitempty"`
+	LastRun *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+	NextRun *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	// replica running a job, empty if no replica does
+	Holder        string                 `protobuf:"bytes,4,opt,name=holder,proto3" json:"holder,omitempty"`
+	HeldSince     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=held_since,json=heldSince,proto3" json:"held_since,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -183,30 +253,1164 @@ func (x *StatusReply) GetStatus() Status {
 	return Status_STATUS_UNSPECIFIED
 }
 
-var File_proto_update_update_proto protoreflect.FileDescriptor
+func (x *StatusReply) GetLastRun() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRun
+	}
+	return nil
+}
 
-const file_proto_update_update_proto_rawDesc = "" +
-	"\n" +
-	"\x19proto/update/update.proto\x12\x06update\x1a\x1bgoogle/protobuf/empty.proto\"\x9a\x01\n" +
-	"\n" +
-	"StatsReply\x12\x1f\n" +
-	"\vwords_total\x18\x01 \x01(\x03R\n" +
-	"wordsTotal\x12!\n" +
-	"\fwords_unique\x18\x02 \x01(\x03R\vwordsUnique\x12!\n" +
-	"\fcomics_total\x18\x03 \x01(\x03R\vcomicsTotal\x12%\n" +
-	"\x0ecomics_fetched\x18\x04 \x01(\x03R\rcomicsFetched\"5\n" +
-	"\vStatusReply\x12&\n" +
-	"\x06status\x18\x01 \x01(\x0e2\x0e.update.StatusR\x06status*E\n" +
-	"\x06Status\x12\x16\n" +
-	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0f\n" +
-	"\vSTATUS_IDLE\x10\x01\x12\x12\n" +
-	"\x0eSTATUS_RUNNING\x10\x022\xa8\x02\n" +
-	"\x06Update\x128\n" +
-	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x127\n" +
-	"\x06Status\x12\x16.google.protobuf.Empty\x1a\x13.update.StatusReply\"\x00\x12:\n" +
-	"\x06Update\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x125\n" +
-	"\x05Stats\x12\x16.google.protobuf.Empty\x1a\x12.update.StatsReply\"\x00\x128\n" +
-	"\x04Drop\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00B\x1fZ\x1dyadro.com/course/proto/updateb\x06proto3"
+func (x *StatusReply) GetNextRun() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRun
+	}
+	return nil
+}
+
+func (x *StatusReply) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *StatusReply) GetHeldSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HeldSince
+	}
+	return nil
+}
+
+type UpdateProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=update.Status" json:"status,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Fetched       int64                  `protobuf:"varint,3,opt,name=fetched,proto3" json:"fetched,omitempty"`
+	Normalized    int64                  `protobuf:"varint,4,opt,name=normalized,proto3" json:"normalized,omitempty"`
+	Stored        int64                  `protobuf:"varint,5,opt,name=stored,proto3" json:"stored,omitempty"`
+	Failed        int64                  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	CurrentId     int64                  `protobuf:"varint,7,opt,name=current_id,json=currentId,proto3" json:"current_id,omitempty"`
+	Changed       int64                  `protobuf:"varint,8,opt,name=changed,proto3" json:"changed,omitempty"`
+	Timings       *StageTimings          `protobuf:"bytes,9,opt,name=timings,proto3" json:"timings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProgress) Reset() {
+	*x = UpdateProgress{}
+	mi := &file_proto_update_update_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProgress) ProtoMessage() {}
+
+func (x *UpdateProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProgress.ProtoReflect.Descriptor instead.
+func (*UpdateProgress) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateProgress) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetFetched() int64 {
+	if x != nil {
+		return x.Fetched
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetNormalized() int64 {
+	if x != nil {
+		return x.Normalized
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetStored() int64 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetCurrentId() int64 {
+	if x != nil {
+		return x.CurrentId
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetChanged() int64 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *UpdateProgress) GetTimings() *StageTimings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
+// time spent by workers of every pipeline stage, summed over workers
+type StageTimings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fetch         *durationpb.Duration   `protobuf:"bytes,1,opt,name=fetch,proto3" json:"fetch,omitempty"`
+	Normalize     *durationpb.Duration   `protobuf:"bytes,2,opt,name=normalize,proto3" json:"normalize,omitempty"`
+	Store         *durationpb.Duration   `protobuf:"bytes,3,opt,name=store,proto3" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageTimings) Reset() {
+	*x = StageTimings{}
+	mi := &file_proto_update_update_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageTimings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageTimings) ProtoMessage() {}
+
+func (x *StageTimings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageTimings.ProtoReflect.Descriptor instead.
+func (*StageTimings) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StageTimings) GetFetch() *durationpb.Duration {
+	if x != nil {
+		return x.Fetch
+	}
+	return nil
+}
+
+func (x *StageTimings) GetNormalize() *durationpb.Duration {
+	if x != nil {
+		return x.Normalize
+	}
+	return nil
+}
+
+func (x *StageTimings) GetStore() *durationpb.Duration {
+	if x != nil {
+		return x.Store
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TriggeredBy   string                 `protobuf:"bytes,1,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+type Job struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Outcome       JobOutcome             `protobuf:"varint,4,opt,name=outcome,proto3,enum=update.JobOutcome" json:"outcome,omitempty"`
+	Total         int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	Fetched       int64                  `protobuf:"varint,6,opt,name=fetched,proto3" json:"fetched,omitempty"`
+	Normalized    int64                  `protobuf:"varint,7,opt,name=normalized,proto3" json:"normalized,omitempty"`
+	Stored        int64                  `protobuf:"varint,8,opt,name=stored,proto3" json:"stored,omitempty"`
+	Failed        int64                  `protobuf:"varint,9,opt,name=failed,proto3" json:"failed,omitempty"`
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	TriggeredBy   string                 `protobuf:"bytes,11,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	Kind          string                 `protobuf:"bytes,12,opt,name=kind,proto3" json:"kind,omitempty"`
+	Changed       int64                  `protobuf:"varint,13,opt,name=changed,proto3" json:"changed,omitempty"`
+	Timings       *StageTimings          `protobuf:"bytes,14,opt,name=timings,proto3" json:"timings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_update_update_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Job) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Job) GetOutcome() JobOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return JobOutcome_JOB_OUTCOME_UNSPECIFIED
+}
+
+func (x *Job) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Job) GetFetched() int64 {
+	if x != nil {
+		return x.Fetched
+	}
+	return 0
+}
+
+func (x *Job) GetNormalized() int64 {
+	if x != nil {
+		return x.Normalized
+	}
+	return 0
+}
+
+func (x *Job) GetStored() int64 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *Job) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+func (x *Job) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Job) GetChanged() int64 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *Job) GetTimings() *StageTimings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
+type JobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRequest) Reset() {
+	*x = JobRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRequest) ProtoMessage() {}
+
+func (x *JobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRequest.ProtoReflect.Descriptor instead.
+func (*JobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListJobsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListJobsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*Job                 `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsReply) Reset() {
+	*x = ListJobsReply{}
+	mi := &file_proto_update_update_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsReply) ProtoMessage() {}
+
+func (x *ListJobsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsReply.ProtoReflect.Descriptor instead.
+func (*ListJobsReply) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListJobsReply) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type FailedComics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Stage         string                 `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	ErrorClass    string                 `protobuf:"bytes,3,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts      int64                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	FailedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailedComics) Reset() {
+	*x = FailedComics{}
+	mi := &file_proto_update_update_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedComics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedComics) ProtoMessage() {}
+
+func (x *FailedComics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedComics.ProtoReflect.Descriptor instead.
+func (*FailedComics) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FailedComics) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FailedComics) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *FailedComics) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *FailedComics) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FailedComics) GetAttempts() int64 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *FailedComics) GetFailedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FailedAt
+	}
+	return nil
+}
+
+type ListFailedReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comics        []*FailedComics        `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFailedReply) Reset() {
+	*x = ListFailedReply{}
+	mi := &file_proto_update_update_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFailedReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFailedReply) ProtoMessage() {}
+
+func (x *ListFailedReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFailedReply.ProtoReflect.Descriptor instead.
+func (*ListFailedReply) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListFailedReply) GetComics() []*FailedComics {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+type QuarantinedComics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	QuarantinedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=quarantined_at,json=quarantinedAt,proto3" json:"quarantined_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantinedComics) Reset() {
+	*x = QuarantinedComics{}
+	mi := &file_proto_update_update_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedComics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedComics) ProtoMessage() {}
+
+func (x *QuarantinedComics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedComics.ProtoReflect.Descriptor instead.
+func (*QuarantinedComics) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QuarantinedComics) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QuarantinedComics) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *QuarantinedComics) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QuarantinedComics) GetQuarantinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QuarantinedAt
+	}
+	return nil
+}
+
+type ListQuarantinedReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comics        []*QuarantinedComics   `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuarantinedReply) Reset() {
+	*x = ListQuarantinedReply{}
+	mi := &file_proto_update_update_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuarantinedReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuarantinedReply) ProtoMessage() {}
+
+func (x *ListQuarantinedReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuarantinedReply.ProtoReflect.Descriptor instead.
+func (*ListQuarantinedReply) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListQuarantinedReply) GetComics() []*QuarantinedComics {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+type RefetchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	TriggeredBy   string                 `protobuf:"bytes,3,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefetchRequest) Reset() {
+	*x = RefetchRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefetchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefetchRequest) ProtoMessage() {}
+
+func (x *RefetchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefetchRequest.ProtoReflect.Descriptor instead.
+func (*RefetchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RefetchRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RefetchRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *RefetchRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+type RefreshRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TriggeredBy     string                 `protobuf:"bytes,1,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	Days            int64                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	EmptyTranscript bool                   `protobuf:"varint,3,opt,name=empty_transcript,json=emptyTranscript,proto3" json:"empty_transcript,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequest) ProtoMessage() {}
+
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RefreshRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+func (x *RefreshRequest) GetDays() int64 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *RefreshRequest) GetEmptyTranscript() bool {
+	if x != nil {
+		return x.EmptyTranscript
+	}
+	return false
+}
+
+type RestoreReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Restored      int64                  `protobuf:"varint,1,opt,name=restored,proto3" json:"restored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreReply) Reset() {
+	*x = RestoreReply{}
+	mi := &file_proto_update_update_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreReply) ProtoMessage() {}
+
+func (x *RestoreReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreReply.ProtoReflect.Descriptor instead.
+func (*RestoreReply) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreReply) GetRestored() int64 {
+	if x != nil {
+		return x.Restored
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_proto_update_update_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type DeleteReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       int64                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReply) Reset() {
+	*x = DeleteReply{}
+	mi := &file_proto_update_update_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReply) ProtoMessage() {}
+
+func (x *DeleteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_update_update_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReply.ProtoReflect.Descriptor instead.
+func (*DeleteReply) Descriptor() ([]byte, []int) {
+	return file_proto_update_update_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteReply) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+var File_proto_update_update_proto protoreflect.FileDescriptor
+
+const file_proto_update_update_proto_rawDesc = "" +
+	"\n" +
+	"\x19proto/update/update.proto\x12\x06update\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbf\x01\n" +
+	"\n" +
+	"StatsReply\x12\x1f\n" +
+	"\vwords_total\x18\x01 \x01(\x03R\n" +
+	"wordsTotal\x12!\n" +
+	"\fwords_unique\x18\x02 \x01(\x03R\vwordsUnique\x12!\n" +
+	"\fcomics_total\x18\x03 \x01(\x03R\vcomicsTotal\x12%\n" +
+	"\x0ecomics_fetched\x18\x04 \x01(\x03R\rcomicsFetched\x12#\n" +
+	"\rcomics_failed\x18\x05 \x01(\x03R\fcomicsFailed\"\xf6\x01\n" +
+	"\vStatusReply\x12&\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x0e.update.StatusR\x06status\x125\n" +
+	"\blast_run\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\alastRun\x125\n" +
+	"\bnext_run\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\anextRun\x12\x16\n" +
+	"\x06holder\x18\x04 \x01(\tR\x06holder\x129\n" +
+	"\n" +
+	"held_since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\theldSince\"\xa1\x02\n" +
+	"\x0eUpdateProgress\x12&\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x0e.update.StatusR\x06status\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x18\n" +
+	"\afetched\x18\x03 \x01(\x03R\afetched\x12\x1e\n" +
+	"\n" +
+	"normalized\x18\x04 \x01(\x03R\n" +
+	"normalized\x12\x16\n" +
+	"\x06stored\x18\x05 \x01(\x03R\x06stored\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x03R\x06failed\x12\x1d\n" +
+	"\n" +
+	"current_id\x18\a \x01(\x03R\tcurrentId\x12\x18\n" +
+	"\achanged\x18\b \x01(\x03R\achanged\x12.\n" +
+	"\atimings\x18\t \x01(\v2\x14.update.StageTimingsR\atimings\"\xa9\x01\n" +
+	"\fStageTimings\x12/\n" +
+	"\x05fetch\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x05fetch\x127\n" +
+	"\tnormalize\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\tnormalize\x12/\n" +
+	"\x05store\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x05store\"2\n" +
+	"\rUpdateRequest\x12!\n" +
+	"\ftriggered_by\x18\x01 \x01(\tR\vtriggeredBy\"\xd2\x03\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12,\n" +
+	"\aoutcome\x18\x04 \x01(\x0e2\x12.update.JobOutcomeR\aoutcome\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12\x18\n" +
+	"\afetched\x18\x06 \x01(\x03R\afetched\x12\x1e\n" +
+	"\n" +
+	"normalized\x18\a \x01(\x03R\n" +
+	"normalized\x12\x16\n" +
+	"\x06stored\x18\b \x01(\x03R\x06stored\x12\x16\n" +
+	"\x06failed\x18\t \x01(\x03R\x06failed\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12!\n" +
+	"\ftriggered_by\x18\v \x01(\tR\vtriggeredBy\x12\x12\n" +
+	"\x04kind\x18\f \x01(\tR\x04kind\x12\x18\n" +
+	"\achanged\x18\r \x01(\x03R\achanged\x12.\n" +
+	"\atimings\x18\x0e \x01(\v2\x14.update.StageTimingsR\atimings\"\x1c\n" +
+	"\n" +
+	"JobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"?\n" +
+	"\x0fListJobsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\"0\n" +
+	"\rListJobsReply\x12\x1f\n" +
+	"\x04jobs\x18\x01 \x03(\v2\v.update.JobR\x04jobs\"\xc0\x01\n" +
+	"\fFailedComics\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x12\x1f\n" +
+	"\verror_class\x18\x03 \x01(\tR\n" +
+	"errorClass\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x03R\battempts\x127\n" +
+	"\tfailed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bfailedAt\"?\n" +
+	"\x0fListFailedReply\x12,\n" +
+	"\x06comics\x18\x01 \x03(\v2\x14.update.FailedComicsR\x06comics\"\x98\x01\n" +
+	"\x11QuarantinedComics\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\tR\apayload\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12A\n" +
+	"\x0equarantined_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rquarantinedAt\"I\n" +
+	"\x14ListQuarantinedReply\x121\n" +
+	"\x06comics\x18\x01 \x03(\v2\x19.update.QuarantinedComicsR\x06comics\"W\n" +
+	"\x0eRefetchRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\x12!\n" +
+	"\ftriggered_by\x18\x03 \x01(\tR\vtriggeredBy\"r\n" +
+	"\x0eRefreshRequest\x12!\n" +
+	"\ftriggered_by\x18\x01 \x01(\tR\vtriggeredBy\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x03R\x04days\x12)\n" +
+	"\x10empty_transcript\x18\x03 \x01(\bR\x0femptyTranscript\"*\n" +
+	"\fRestoreReply\x12\x1a\n" +
+	"\brestored\x18\x01 \x01(\x03R\brestored\"3\n" +
+	"\rDeleteRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\"'\n" +
+	"\vDeleteReply\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x03R\adeleted*E\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSTATUS_IDLE\x10\x01\x12\x12\n" +
+	"\x0eSTATUS_RUNNING\x10\x02*\x8f\x01\n" +
+	"\n" +
+	"JobOutcome\x12\x1b\n" +
+	"\x17JOB_OUTCOME_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13JOB_OUTCOME_RUNNING\x10\x01\x12\x19\n" +
+	"\x15JOB_OUTCOME_SUCCEEDED\x10\x02\x12\x16\n" +
+	"\x12JOB_OUTCOME_FAILED\x10\x03\x12\x18\n" +
+	"\x14JOB_OUTCOME_CANCELED\x10\x042\xd0\a\n" +
+	"\x06Update\x128\n" +
+	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x127\n" +
+	"\x06Status\x12\x16.google.protobuf.Empty\x1a\x13.update.StatusReply\"\x00\x12.\n" +
+	"\x06Update\x12\x15.update.UpdateRequest\x1a\v.update.Job\"\x00\x125\n" +
+	"\x05Stats\x12\x16.google.protobuf.Empty\x1a\x12.update.StatsReply\"\x00\x128\n" +
+	"\x04Drop\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x12A\n" +
+	"\vWatchUpdate\x12\x16.google.protobuf.Empty\x1a\x16.update.UpdateProgress\"\x000\x01\x12+\n" +
+	"\x06GetJob\x12\x12.update.JobRequest\x1a\v.update.Job\"\x00\x12<\n" +
+	"\bListJobs\x12\x17.update.ListJobsRequest\x1a\x15.update.ListJobsReply\"\x00\x129\n" +
+	"\tCancelJob\x12\x12.update.JobRequest\x1a\x16.google.protobuf.Empty\"\x00\x123\n" +
+	"\vRetryFailed\x12\x15.update.UpdateRequest\x1a\v.update.Job\"\x00\x12?\n" +
+	"\n" +
+	"ListFailed\x12\x16.google.protobuf.Empty\x1a\x17.update.ListFailedReply\"\x00\x121\n" +
+	"\tReprocess\x12\x15.update.UpdateRequest\x1a\v.update.Job\"\x00\x12I\n" +
+	"\x0fListQuarantined\x12\x16.google.protobuf.Empty\x1a\x1c.update.ListQuarantinedReply\"\x00\x120\n" +
+	"\aRefetch\x12\x16.update.RefetchRequest\x1a\v.update.Job\"\x00\x126\n" +
+	"\x06Delete\x12\x15.update.DeleteRequest\x1a\x13.update.DeleteReply\"\x00\x120\n" +
+	"\aRefresh\x12\x16.update.RefreshRequest\x1a\v.update.Job\"\x00\x129\n" +
+	"\aRestore\x12\x16.google.protobuf.Empty\x1a\x14.update.RestoreReply\"\x00B\x1fZ\x1dyadro.com/course/proto/updateb\x06proto3"
 
 var (
 	file_proto_update_update_proto_rawDescOnce sync.Once
@@ -220,31 +1424,91 @@ func file_proto_update_update_proto_rawDescGZIP() []byte {
 	return file_proto_update_update_proto_rawDescData
 }
 
-var file_proto_update_update_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_update_update_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_update_update_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_update_update_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_update_update_proto_goTypes = []any{
-	(Status)(0),           // 0: update.Status
-	(*StatsReply)(nil),    // 1: update.StatsReply
-	(*StatusReply)(nil),   // 2: update.StatusReply
-	(*emptypb.Empty)(nil), // 3: google.protobuf.Empty
+	(Status)(0),                   // 0: update.Status
+	(JobOutcome)(0),               // 1: update.JobOutcome
+	(*StatsReply)(nil),            // 2: update.StatsReply
+	(*StatusReply)(nil),           // 3: update.StatusReply
+	(*UpdateProgress)(nil),        // 4: update.UpdateProgress
+	(*StageTimings)(nil),          // 5: update.StageTimings
+	(*UpdateRequest)(nil),         // 6: update.UpdateRequest
+	(*Job)(nil),                   // 7: update.Job
+	(*JobRequest)(nil),            // 8: update.JobRequest
+	(*ListJobsRequest)(nil),       // 9: update.ListJobsRequest
+	(*ListJobsReply)(nil),         // 10: update.ListJobsReply
+	(*FailedComics)(nil),          // 11: update.FailedComics
+	(*ListFailedReply)(nil),       // 12: update.ListFailedReply
+	(*QuarantinedComics)(nil),     // 13: update.QuarantinedComics
+	(*ListQuarantinedReply)(nil),  // 14: update.ListQuarantinedReply
+	(*RefetchRequest)(nil),        // 15: update.RefetchRequest
+	(*RefreshRequest)(nil),        // 16: update.RefreshRequest
+	(*RestoreReply)(nil),          // 17: update.RestoreReply
+	(*DeleteRequest)(nil),         // 18: update.DeleteRequest
+	(*DeleteReply)(nil),           // 19: update.DeleteReply
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 21: google.protobuf.Duration
+	(*emptypb.Empty)(nil),         // 22: google.protobuf.Empty
 }
 var file_proto_update_update_proto_depIdxs = []int32{
-	0, // 0: update.StatusReply.status:type_name -> update.Status
-	3, // 1: update.Update.Ping:input_type -> google.protobuf.Empty
-	3, // 2: update.Update.Status:input_type -> google.protobuf.Empty
-	3, // 3: update.Update.Update:input_type -> google.protobuf.Empty
-	3, // 4: update.Update.Stats:input_type -> google.protobuf.Empty
-	3, // 5: update.Update.Drop:input_type -> google.protobuf.Empty
-	3, // 6: update.Update.Ping:output_type -> google.protobuf.Empty
-	2, // 7: update.Update.Status:output_type -> update.StatusReply
-	3, // 8: update.Update.Update:output_type -> google.protobuf.Empty
-	1, // 9: update.Update.Stats:output_type -> update.StatsReply
-	3, // 10: update.Update.Drop:output_type -> google.protobuf.Empty
-	6, // [6:11] is the sub-list for method output_type
-	1, // [1:6] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: update.StatusReply.status:type_name -> update.Status
+	20, // 1: update.StatusReply.last_run:type_name -> google.protobuf.Timestamp
+	20, // 2: update.StatusReply.next_run:type_name -> google.protobuf.Timestamp
+	20, // 3: update.StatusReply.held_since:type_name -> google.protobuf.Timestamp
+	0,  // 4: update.UpdateProgress.status:type_name -> update.Status
+	5,  // 5: update.UpdateProgress.timings:type_name -> update.StageTimings
+	21, // 6: update.StageTimings.fetch:type_name -> google.protobuf.Duration
+	21, // 7: update.StageTimings.normalize:type_name -> google.protobuf.Duration
+	21, // 8: update.StageTimings.store:type_name -> google.protobuf.Duration
+	20, // 9: update.Job.started_at:type_name -> google.protobuf.Timestamp
+	20, // 10: update.Job.finished_at:type_name -> google.protobuf.Timestamp
+	1,  // 11: update.Job.outcome:type_name -> update.JobOutcome
+	5,  // 12: update.Job.timings:type_name -> update.StageTimings
+	7,  // 13: update.ListJobsReply.jobs:type_name -> update.Job
+	20, // 14: update.FailedComics.failed_at:type_name -> google.protobuf.Timestamp
+	11, // 15: update.ListFailedReply.comics:type_name -> update.FailedComics
+	20, // 16: update.QuarantinedComics.quarantined_at:type_name -> google.protobuf.Timestamp
+	13, // 17: update.ListQuarantinedReply.comics:type_name -> update.QuarantinedComics
+	22, // 18: update.Update.Ping:input_type -> google.protobuf.Empty
+	22, // 19: update.Update.Status:input_type -> google.protobuf.Empty
+	6,  // 20: update.Update.Update:input_type -> update.UpdateRequest
+	22, // 21: update.Update.Stats:input_type -> google.protobuf.Empty
+	22, // 22: update.Update.Drop:input_type -> google.protobuf.Empty
+	22, // 23: update.Update.WatchUpdate:input_type -> google.protobuf.Empty
+	8,  // 24: update.Update.GetJob:input_type -> update.JobRequest
+	9,  // 25: update.Update.ListJobs:input_type -> update.ListJobsRequest
+	8,  // 26: update.Update.CancelJob:input_type -> update.JobRequest
+	6,  // 27: update.Update.RetryFailed:input_type -> update.UpdateRequest
+	22, // 28: update.Update.ListFailed:input_type -> google.protobuf.Empty
+	6,  // 29: update.Update.Reprocess:input_type -> update.UpdateRequest
+	22, // 30: update.Update.ListQuarantined:input_type -> google.protobuf.Empty
+	15, // 31: update.Update.Refetch:input_type -> update.RefetchRequest
+	18, // 32: update.Update.Delete:input_type -> update.DeleteRequest
+	16, // 33: update.Update.Refresh:input_type -> update.RefreshRequest
+	22, // 34: update.Update.Restore:input_type -> google.protobuf.Empty
+	22, // 35: update.Update.Ping:output_type -> google.protobuf.Empty
+	3,  // 36: update.Update.Status:output_type -> update.StatusReply
+	7,  // 37: update.Update.Update:output_type -> update.Job
+	2,  // 38: update.Update.Stats:output_type -> update.StatsReply
+	22, // 39: update.Update.Drop:output_type -> google.protobuf.Empty
+	4,  // 40: update.Update.WatchUpdate:output_type -> update.UpdateProgress
+	7,  // 41: update.Update.GetJob:output_type -> update.Job
+	10, // 42: update.Update.ListJobs:output_type -> update.ListJobsReply
+	22, // 43: update.Update.CancelJob:output_type -> google.protobuf.Empty
+	7,  // 44: update.Update.RetryFailed:output_type -> update.Job
+	12, // 45: update.Update.ListFailed:output_type -> update.ListFailedReply
+	7,  // 46: update.Update.Reprocess:output_type -> update.Job
+	14, // 47: update.Update.ListQuarantined:output_type -> update.ListQuarantinedReply
+	7,  // 48: update.Update.Refetch:output_type -> update.Job
+	19, // 49: update.Update.Delete:output_type -> update.DeleteReply
+	7,  // 50: update.Update.Refresh:output_type -> update.Job
+	17, // 51: update.Update.Restore:output_type -> update.RestoreReply
+	35, // [35:52] is the sub-list for method output_type
+	18, // [18:35] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_proto_update_update_proto_init() }
@@ -257,8 +1521,8 @@ func file_proto_update_update_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_update_update_proto_rawDesc), len(file_proto_update_update_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
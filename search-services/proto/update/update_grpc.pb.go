@@ -20,11 +20,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Update_Ping_FullMethodName   = "/update.Update/Ping"
-	Update_Status_FullMethodName = "/update.Update/Status"
-	Update_Update_FullMethodName = "/update.Update/Update"
-	Update_Stats_FullMethodName  = "/update.Update/Stats"
-	Update_Drop_FullMethodName   = "/update.Update/Drop"
+	Update_Ping_FullMethodName            = "/update.Update/Ping"
+	Update_Status_FullMethodName          = "/update.Update/Status"
+	Update_Update_FullMethodName          = "/update.Update/Update"
+	Update_Stats_FullMethodName           = "/update.Update/Stats"
+	Update_Drop_FullMethodName            = "/update.Update/Drop"
+	Update_WatchUpdate_FullMethodName     = "/update.Update/WatchUpdate"
+	Update_GetJob_FullMethodName          = "/update.Update/GetJob"
+	Update_ListJobs_FullMethodName        = "/update.Update/ListJobs"
+	Update_CancelJob_FullMethodName       = "/update.Update/CancelJob"
+	Update_RetryFailed_FullMethodName     = "/update.Update/RetryFailed"
+	Update_ListFailed_FullMethodName      = "/update.Update/ListFailed"
+	Update_Reprocess_FullMethodName       = "/update.Update/Reprocess"
+	Update_ListQuarantined_FullMethodName = "/update.Update/ListQuarantined"
+	Update_Refetch_FullMethodName         = "/update.Update/Refetch"
+	Update_Delete_FullMethodName          = "/update.Update/Delete"
+	Update_Refresh_FullMethodName         = "/update.Update/Refresh"
+	Update_Restore_FullMethodName         = "/update.Update/Restore"
 )
 
 // UpdateClient is the client API for Update service.
@@ -33,9 +45,21 @@ const (
 type UpdateClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusReply, error)
-	Update(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error)
 	Stats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatsReply, error)
 	Drop(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	WatchUpdate(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UpdateProgress], error)
+	GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Job, error)
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsReply, error)
+	CancelJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RetryFailed(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error)
+	ListFailed(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListFailedReply, error)
+	Reprocess(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error)
+	ListQuarantined(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListQuarantinedReply, error)
+	Refetch(ctx context.Context, in *RefetchRequest, opts ...grpc.CallOption) (*Job, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteReply, error)
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*Job, error)
+	Restore(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RestoreReply, error)
 }
 
 type updateClient struct {
@@ -66,9 +90,9 @@ func (c *updateClient) Status(ctx context.Context, in *emptypb.Empty, opts ...gr
 	return out, nil
 }
 
-func (c *updateClient) Update(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *updateClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(emptypb.Empty)
+	out := new(Job)
 	err := c.cc.Invoke(ctx, Update_Update_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -96,15 +120,156 @@ func (c *updateClient) Drop(ctx context.Context, in *emptypb.Empty, opts ...grpc
 	return out, nil
 }
 
+func (c *updateClient) WatchUpdate(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UpdateProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Update_ServiceDesc.Streams[0], Update_WatchUpdate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[emptypb.Empty, UpdateProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Update_WatchUpdateClient = grpc.ServerStreamingClient[UpdateProgress]
+
+func (c *updateClient) GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Update_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsReply)
+	err := c.cc.Invoke(ctx, Update_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) CancelJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Update_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) RetryFailed(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Update_RetryFailed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) ListFailed(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListFailedReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFailedReply)
+	err := c.cc.Invoke(ctx, Update_ListFailed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) Reprocess(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Update_Reprocess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) ListQuarantined(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListQuarantinedReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListQuarantinedReply)
+	err := c.cc.Invoke(ctx, Update_ListQuarantined_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) Refetch(ctx context.Context, in *RefetchRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Update_Refetch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteReply)
+	err := c.cc.Invoke(ctx, Update_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Update_Refresh_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *updateClient) Restore(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RestoreReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreReply)
+	err := c.cc.Invoke(ctx, Update_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UpdateServer is the server API for Update service.
 // All implementations must embed UnimplementedUpdateServer
 // for forward compatibility.
 type UpdateServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Status(context.Context, *emptypb.Empty) (*StatusReply, error)
-	Update(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	Update(context.Context, *UpdateRequest) (*Job, error)
 	Stats(context.Context, *emptypb.Empty) (*StatsReply, error)
 	Drop(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	WatchUpdate(*emptypb.Empty, grpc.ServerStreamingServer[UpdateProgress]) error
+	GetJob(context.Context, *JobRequest) (*Job, error)
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsReply, error)
+	CancelJob(context.Context, *JobRequest) (*emptypb.Empty, error)
+	RetryFailed(context.Context, *UpdateRequest) (*Job, error)
+	ListFailed(context.Context, *emptypb.Empty) (*ListFailedReply, error)
+	Reprocess(context.Context, *UpdateRequest) (*Job, error)
+	ListQuarantined(context.Context, *emptypb.Empty) (*ListQuarantinedReply, error)
+	Refetch(context.Context, *RefetchRequest) (*Job, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteReply, error)
+	Refresh(context.Context, *RefreshRequest) (*Job, error)
+	Restore(context.Context, *emptypb.Empty) (*RestoreReply, error)
 	mustEmbedUnimplementedUpdateServer()
 }
 
@@ -121,7 +286,7 @@ func (UnimplementedUpdateServer) Ping(context.Context, *emptypb.Empty) (*emptypb
 func (UnimplementedUpdateServer) Status(context.Context, *emptypb.Empty) (*StatusReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
-func (UnimplementedUpdateServer) Update(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+func (UnimplementedUpdateServer) Update(context.Context, *UpdateRequest) (*Job, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
 func (UnimplementedUpdateServer) Stats(context.Context, *emptypb.Empty) (*StatsReply, error) {
@@ -130,6 +295,42 @@ func (UnimplementedUpdateServer) Stats(context.Context, *emptypb.Empty) (*StatsR
 func (UnimplementedUpdateServer) Drop(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Drop not implemented")
 }
+func (UnimplementedUpdateServer) WatchUpdate(*emptypb.Empty, grpc.ServerStreamingServer[UpdateProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUpdate not implemented")
+}
+func (UnimplementedUpdateServer) GetJob(context.Context, *JobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedUpdateServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedUpdateServer) CancelJob(context.Context, *JobRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedUpdateServer) RetryFailed(context.Context, *UpdateRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryFailed not implemented")
+}
+func (UnimplementedUpdateServer) ListFailed(context.Context, *emptypb.Empty) (*ListFailedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFailed not implemented")
+}
+func (UnimplementedUpdateServer) Reprocess(context.Context, *UpdateRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reprocess not implemented")
+}
+func (UnimplementedUpdateServer) ListQuarantined(context.Context, *emptypb.Empty) (*ListQuarantinedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuarantined not implemented")
+}
+func (UnimplementedUpdateServer) Refetch(context.Context, *RefetchRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refetch not implemented")
+}
+func (UnimplementedUpdateServer) Delete(context.Context, *DeleteRequest) (*DeleteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedUpdateServer) Refresh(context.Context, *RefreshRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (UnimplementedUpdateServer) Restore(context.Context, *emptypb.Empty) (*RestoreReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 func (UnimplementedUpdateServer) mustEmbedUnimplementedUpdateServer() {}
 func (UnimplementedUpdateServer) testEmbeddedByValue()                {}
 
@@ -188,7 +389,7 @@ func _Update_Status_Handler(srv interface{}, ctx context.Context, dec func(inter
 }
 
 func _Update_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(UpdateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -200,7 +401,7 @@ func _Update_Update_Handler(srv interface{}, ctx context.Context, dec func(inter
 		FullMethod: Update_Update_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UpdateServer).Update(ctx, req.(*emptypb.Empty))
+		return srv.(UpdateServer).Update(ctx, req.(*UpdateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -241,6 +442,215 @@ func _Update_Drop_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Update_WatchUpdate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UpdateServer).WatchUpdate(m, &grpc.GenericServerStream[emptypb.Empty, UpdateProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Update_WatchUpdateServer = grpc.ServerStreamingServer[UpdateProgress]
+
+func _Update_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).GetJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).CancelJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_RetryFailed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).RetryFailed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_RetryFailed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).RetryFailed(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_ListFailed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).ListFailed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_ListFailed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).ListFailed(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_Reprocess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).Reprocess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_Reprocess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).Reprocess(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_ListQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).ListQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_ListQuarantined_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).ListQuarantined(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_Refetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefetchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).Refetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_Refetch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).Refetch(ctx, req.(*RefetchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_Refresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Update_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpdateServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Update_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpdateServer).Restore(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Update_ServiceDesc is the grpc.ServiceDesc for Update service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,7 +678,57 @@ var Update_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Drop",
 			Handler:    _Update_Drop_Handler,
 		},
+		{
+			MethodName: "GetJob",
+			Handler:    _Update_GetJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Update_ListJobs_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _Update_CancelJob_Handler,
+		},
+		{
+			MethodName: "RetryFailed",
+			Handler:    _Update_RetryFailed_Handler,
+		},
+		{
+			MethodName: "ListFailed",
+			Handler:    _Update_ListFailed_Handler,
+		},
+		{
+			MethodName: "Reprocess",
+			Handler:    _Update_Reprocess_Handler,
+		},
+		{
+			MethodName: "ListQuarantined",
+			Handler:    _Update_ListQuarantined_Handler,
+		},
+		{
+			MethodName: "Refetch",
+			Handler:    _Update_Refetch_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Update_Delete_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Update_Refresh_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Update_Restore_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUpdate",
+			Handler:       _Update_WatchUpdate_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/update/update.proto",
 }
@@ -23,10 +23,12 @@ const (
 )
 
 type WordsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Phrase string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
+	// keep_duplicates keeps every occurrence of a word in the phrase order.
+	KeepDuplicates bool `protobuf:"varint,2,opt,name=keep_duplicates,json=keepDuplicates,proto3" json:"keep_duplicates,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *WordsRequest) Reset() {
@@ -66,6 +68,13 @@ func (x *WordsRequest) GetPhrase() string {
 	return ""
 }
 
+func (x *WordsRequest) GetKeepDuplicates() bool {
+	if x != nil {
+		return x.KeepDuplicates
+	}
+	return false
+}
+
 type WordsReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Words         []string               `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
@@ -110,19 +119,67 @@ func (x *WordsReply) GetWords() []string {
 	return nil
 }
 
+type VersionReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionReply) Reset() {
+	*x = VersionReply{}
+	mi := &file_proto_words_words_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionReply) ProtoMessage() {}
+
+func (x *VersionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionReply.ProtoReflect.Descriptor instead.
+func (*VersionReply) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VersionReply) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 var File_proto_words_words_proto protoreflect.FileDescriptor
 
 const file_proto_words_words_proto_rawDesc = "" +
 	"\n" +
-	"\x17proto/words/words.proto\x12\x05words\x1a\x1bgoogle/protobuf/empty.proto\"&\n" +
+	"\x17proto/words/words.proto\x12\x05words\x1a\x1bgoogle/protobuf/empty.proto\"O\n" +
 	"\fWordsRequest\x12\x16\n" +
-	"\x06phrase\x18\x01 \x01(\tR\x06phrase\"\"\n" +
+	"\x06phrase\x18\x01 \x01(\tR\x06phrase\x12'\n" +
+	"\x0fkeep_duplicates\x18\x02 \x01(\bR\x0ekeepDuplicates\"\"\n" +
 	"\n" +
 	"WordsReply\x12\x14\n" +
-	"\x05words\x18\x01 \x03(\tR\x05words2s\n" +
+	"\x05words\x18\x01 \x03(\tR\x05words\"(\n" +
+	"\fVersionReply\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion2\xad\x01\n" +
 	"\x05Words\x128\n" +
 	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x120\n" +
-	"\x04Norm\x12\x13.words.WordsRequest\x1a\x11.words.WordsReply\"\x00B\x1eZ\x1cyadro.com/course/proto/wordsb\x06proto3"
+	"\x04Norm\x12\x13.words.WordsRequest\x1a\x11.words.WordsReply\"\x00\x128\n" +
+	"\aVersion\x12\x16.google.protobuf.Empty\x1a\x13.words.VersionReply\"\x00B\x1eZ\x1cyadro.com/course/proto/wordsb\x06proto3"
 
 var (
 	file_proto_words_words_proto_rawDescOnce sync.Once
@@ -136,19 +193,22 @@ func file_proto_words_words_proto_rawDescGZIP() []byte {
 	return file_proto_words_words_proto_rawDescData
 }
 
-var file_proto_words_words_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_words_words_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_proto_words_words_proto_goTypes = []any{
 	(*WordsRequest)(nil),  // 0: words.WordsRequest
 	(*WordsReply)(nil),    // 1: words.WordsReply
-	(*emptypb.Empty)(nil), // 2: google.protobuf.Empty
+	(*VersionReply)(nil),  // 2: words.VersionReply
+	(*emptypb.Empty)(nil), // 3: google.protobuf.Empty
 }
 var file_proto_words_words_proto_depIdxs = []int32{
-	2, // 0: words.Words.Ping:input_type -> google.protobuf.Empty
+	3, // 0: words.Words.Ping:input_type -> google.protobuf.Empty
 	0, // 1: words.Words.Norm:input_type -> words.WordsRequest
-	2, // 2: words.Words.Ping:output_type -> google.protobuf.Empty
-	1, // 3: words.Words.Norm:output_type -> words.WordsReply
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	3, // 2: words.Words.Version:input_type -> google.protobuf.Empty
+	3, // 3: words.Words.Ping:output_type -> google.protobuf.Empty
+	1, // 4: words.Words.Norm:output_type -> words.WordsReply
+	2, // 5: words.Words.Version:output_type -> words.VersionReply
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -165,7 +225,7 @@ func file_proto_words_words_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_words_words_proto_rawDesc), len(file_proto_words_words_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
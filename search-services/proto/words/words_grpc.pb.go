@@ -20,8 +20,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Words_Ping_FullMethodName = "/words.Words/Ping"
-	Words_Norm_FullMethodName = "/words.Words/Norm"
+	Words_Ping_FullMethodName    = "/words.Words/Ping"
+	Words_Norm_FullMethodName    = "/words.Words/Norm"
+	Words_Version_FullMethodName = "/words.Words/Version"
 )
 
 // WordsClient is the client API for Words service.
@@ -33,6 +34,7 @@ type WordsClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Send name, receive greeting
 	Norm(ctx context.Context, in *WordsRequest, opts ...grpc.CallOption) (*WordsReply, error)
+	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionReply, error)
 }
 
 type wordsClient struct {
@@ -63,6 +65,16 @@ func (c *wordsClient) Norm(ctx context.Context, in *WordsRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *wordsClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VersionReply)
+	err := c.cc.Invoke(ctx, Words_Version_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WordsServer is the server API for Words service.
 // All implementations must embed UnimplementedWordsServer
 // for forward compatibility.
@@ -72,6 +84,7 @@ type WordsServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	// Send name, receive greeting
 	Norm(context.Context, *WordsRequest) (*WordsReply, error)
+	Version(context.Context, *emptypb.Empty) (*VersionReply, error)
 	mustEmbedUnimplementedWordsServer()
 }
 
@@ -88,6 +101,9 @@ func (UnimplementedWordsServer) Ping(context.Context, *emptypb.Empty) (*emptypb.
 func (UnimplementedWordsServer) Norm(context.Context, *WordsRequest) (*WordsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Norm not implemented")
 }
+func (UnimplementedWordsServer) Version(context.Context, *emptypb.Empty) (*VersionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
 func (UnimplementedWordsServer) mustEmbedUnimplementedWordsServer() {}
 func (UnimplementedWordsServer) testEmbeddedByValue()               {}
 
@@ -145,6 +161,24 @@ func _Words_Norm_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Words_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordsServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Words_Version_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordsServer).Version(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Words_ServiceDesc is the grpc.ServiceDesc for Words service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -160,6 +194,10 @@ var Words_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Norm",
 			Handler:    _Words_Norm_Handler,
 		},
+		{
+			MethodName: "Version",
+			Handler:    _Words_Version_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/words/words.proto",
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 	"strconv"
 	"strings"
@@ -34,12 +36,14 @@ func New(log *slog.Logger, address string) (*DB, error) {
 func (db *DB) Find(ctx context.Context, words []string, limit int) (*core.SearchReply, error) {
 	db.log.Info("Start searching comics for words: " + strings.Join(words, ", "))
 	query := `
-    SELECT id, url FROM comics 
+    SELECT id, url, title, safe_title, alt, transcript, published,
+	ARRAY(SELECT DISTINCT word FROM unnest(words) AS word WHERE word = ANY($1::text[])) AS matched
+	FROM comics 
 	WHERE words && $1::text[] 
 	ORDER BY 
-	CASE WHEN (SELECT COUNT(*) FROM unnest(words) AS word WHERE word = ANY($1::text[])) = array_length($1::text[], 1) THEN 0 ELSE 1 END,
+	CASE WHEN (SELECT COUNT(DISTINCT word) FROM unnest(words) AS word WHERE word = ANY($1::text[])) = array_length($1::text[], 1) THEN 0 ELSE 1 END,
 	(
-		SELECT COUNT(*) 
+		SELECT COUNT(DISTINCT word) 
 		FROM unnest(words) AS word 
 		WHERE word = ANY($1::text[])
 	) DESC,
@@ -59,13 +63,16 @@ func (db *DB) Find(ctx context.Context, words []string, limit int) (*core.Search
 
 	var comics []core.Comics
 	for rows.Next() {
-		var comic core.Comics
-		err := rows.Scan(&comic.ID, &comic.URL)
+		var row comicsRow
+		var matched pq.StringArray
+		err := rows.Scan(&row.ID, &row.URL, &row.Title, &row.SafeTitle, &row.Alt, &row.Transcript, &row.Published, &matched)
 		if err != nil {
 			db.log.Error("Failed to scan comics", "error", err)
 			return &core.SearchReply{}, err
 		}
-		comics = append(comics, comic)
+		found := row.toCore()
+		found.Matched = matched
+		comics = append(comics, found)
 	}
 	db.log.Info("All information about needed comics has been recieved. Total amount of comics: " + strconv.Itoa(len(comics)))
 	return &core.SearchReply{Comics: comics}, nil
@@ -114,16 +121,116 @@ func (db *DB) FindAll(ctx context.Context) (*core.IndexInfo, error) {
 	return &core.IndexInfo{Comics: comics}, nil
 }
 
+// FindByIds loads words of comics with the given ids, comics missing in db
+// are skipped.
+func (db *DB) FindByIds(ctx context.Context, ids []int) (*core.IndexInfo, error) {
+	db.log.Info("Start load words of comics", "comics", len(ids))
+
+	type row struct {
+		Word       string        `db:"word"`
+		Comics_ids pq.Int32Array `db:"comics_ids"`
+	}
+
+	var rows []row
+	err := db.conn.SelectContext(ctx, &rows, `
+        SELECT
+            word,
+            array_agg(id) as comics_ids
+        FROM (
+            SELECT id, unnest(words) as word
+            FROM comics
+            WHERE id = ANY($1::int[])
+        ) expanded
+        GROUP BY word;
+    `, pq.Array(ids))
+	if err != nil {
+		db.log.Error("Failed to load words of comics", "error", err)
+		return &core.IndexInfo{}, err
+	}
+
+	comics := make([]core.IndexInfoOne, len(rows))
+	for i, r := range rows {
+		comicIDs := make([]int, len(r.Comics_ids))
+		for j, id := range r.Comics_ids {
+			comicIDs[j] = int(id)
+		}
+		comics[i] = core.IndexInfoOne{Word: r.Word, Comics_ids: comicIDs}
+	}
+	return &core.IndexInfo{Comics: comics}, nil
+}
+
 func (db *DB) GetById(ctx context.Context, id int) (*core.Comics, error) {
 	db.log.Info("Start to load comics with id: " + strconv.Itoa(id))
-	query := `SELECT url FROM comics WHERE id = $1`
-	var url string
+	query := `
+	SELECT id, url, title, safe_title, alt, transcript, published
+	FROM comics WHERE id = $1
+	`
+	var row comicsRow
 
-	err := db.conn.GetContext(ctx, &url, query, id)
+	err := db.conn.GetContext(ctx, &row, query, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &core.Comics{}, core.ErrNotFound
+		}
 		db.log.Error("Failed to find comics with id: "+strconv.Itoa(id), "error", err)
 		return &core.Comics{}, err
 	}
 
-	return &core.Comics{ID: id, URL: url}, nil
+	comic := row.toCore()
+	return &comic, nil
+}
+
+func (db *DB) List(ctx context.Context, offset, limit int, order core.Order) ([]core.Comics, error) {
+	db.log.Info("Start to list comics", "offset", offset, "limit", limit, "order", order)
+	direction := "ASC"
+	if order == core.OrderDesc {
+		direction = "DESC"
+	}
+	query := `
+	SELECT id, url, title, safe_title, alt, transcript, published
+	FROM comics ORDER BY id ` + direction + `
+	OFFSET $1 LIMIT $2
+	`
+	var rows []comicsRow
+	if err := db.conn.SelectContext(ctx, &rows, query, offset, limit); err != nil {
+		db.log.Error("Failed to list comics", "error", err)
+		return nil, err
+	}
+
+	comics := make([]core.Comics, len(rows))
+	for i, row := range rows {
+		comics[i] = row.toCore()
+	}
+	return comics, nil
+}
+
+func (db *DB) Count(ctx context.Context) (int, error) {
+	var total int
+	if err := db.conn.GetContext(ctx, &total, "SELECT COUNT(*) FROM comics"); err != nil {
+		db.log.Error("Failed to count comics", "error", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+type comicsRow struct {
+	ID         int          `db:"id"`
+	URL        string       `db:"url"`
+	Title      string       `db:"title"`
+	SafeTitle  string       `db:"safe_title"`
+	Alt        string       `db:"alt"`
+	Transcript string       `db:"transcript"`
+	Published  sql.NullTime `db:"published"`
+}
+
+func (r comicsRow) toCore() core.Comics {
+	return core.Comics{
+		ID:         r.ID,
+		URL:        r.URL,
+		Title:      r.Title,
+		SafeTitle:  r.SafeTitle,
+		Alt:        r.Alt,
+		Transcript: r.Transcript,
+		Published:  r.Published.Time,
+	}
 }
@@ -2,8 +2,12 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	searchpb "yadro.com/course/proto/search"
 	"yadro.com/course/search/core"
 )
@@ -17,7 +21,11 @@ func NewServer(service core.Searcher) *Server {
 	return &Server{service: service}
 }
 
-func (s *Server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+// Ping fails with FailedPrecondition until the index has been built.
+func (s *Server) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.service.Ready(ctx); err != nil {
+		return nil, toStatus(err)
+	}
 	return nil, nil
 }
 
@@ -29,7 +37,7 @@ func (s *Server) Search(ctx context.Context, in *searchpb.ComicsRequest) (*searc
 
 	response := make([]*searchpb.Comics, len(reply.Comics))
 	for index, comic := range reply.Comics {
-		response[index] = &searchpb.Comics{Id: int64(comic.ID), Url: comic.URL}
+		response[index] = toProto(comic)
 	}
 	return &searchpb.ComicsResponse{Comics: response}, nil
 }
@@ -37,12 +45,84 @@ func (s *Server) Search(ctx context.Context, in *searchpb.ComicsRequest) (*searc
 func (s *Server) SearchIndex(ctx context.Context, in *searchpb.ComicsRequest) (*searchpb.ComicsResponse, error) {
 	reply, err := s.service.SearchIndex(ctx, core.SearchRequest{Limit: int(in.Limit), Phrase: in.Words})
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	response := make([]*searchpb.Comics, len(reply.Comics))
 	for index, comic := range reply.Comics {
-		response[index] = &searchpb.Comics{Id: int64(comic.ID), Url: comic.URL}
+		response[index] = toProto(comic)
 	}
 	return &searchpb.ComicsResponse{Comics: response}, nil
 }
+
+func (s *Server) GetComics(ctx context.Context, in *searchpb.ComicsIdRequest) (*searchpb.Comics, error) {
+	comic, err := s.service.Comics(ctx, int(in.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(*comic), nil
+}
+
+func (s *Server) ListComics(ctx context.Context, in *searchpb.ListComicsRequest) (*searchpb.ListComicsResponse, error) {
+	request := core.ListRequest{Offset: int(in.Offset), Limit: int(in.Limit)}
+	switch in.Order {
+	case searchpb.Order_ORDER_ASC:
+		request.Order = core.OrderAsc
+	case searchpb.Order_ORDER_DESC:
+		request.Order = core.OrderDesc
+	}
+
+	reply, err := s.service.List(ctx, request)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := make([]*searchpb.Comics, len(reply.Comics))
+	for index, comic := range reply.Comics {
+		response[index] = toProto(comic)
+	}
+	return &searchpb.ListComicsResponse{Comics: response, Total: int64(reply.Total)}, nil
+}
+
+func (s *Server) IndexStats(ctx context.Context, _ *emptypb.Empty) (*searchpb.IndexStatsReply, error) {
+	stats, err := s.service.IndexStats(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &searchpb.IndexStatsReply{
+		Generation: stats.Generation,
+		BuiltAt:    timestamppb.New(stats.BuiltAt),
+		Words:      int64(stats.Words),
+		Comics:     int64(stats.Comics),
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, core.ErrBadArguments):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, core.ErrNotReady):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+func toProto(comic core.Comics) *searchpb.Comics {
+	result := &searchpb.Comics{
+		Id:         int64(comic.ID),
+		Url:        comic.URL,
+		Title:      comic.Title,
+		SafeTitle:  comic.SafeTitle,
+		Alt:        comic.Alt,
+		Transcript: comic.Transcript,
+		Score:      comic.Score,
+		Matched:    comic.Matched,
+		Missing:    comic.Missing,
+	}
+	if !comic.Published.IsZero() {
+		result.Published = timestamppb.New(comic.Published)
+	}
+	return result
+}
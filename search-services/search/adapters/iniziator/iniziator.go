@@ -6,62 +6,188 @@ import (
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"yadro.com/course/events"
 	"yadro.com/course/search/core"
 )
 
+const (
+	setupTimeout = 10 * time.Second
+	// nakDelay is the delay before an event is delivered again after the index
+	// has failed to update.
+	nakDelay = 5 * time.Second
+	// buildMinDelay and buildMaxDelay bound the delay between attempts to
+	// build the index on start, it is doubled after every failed attempt.
+	buildMinDelay = time.Second
+	buildMaxDelay = 30 * time.Second
+)
+
+// Iniziator builds the index on start and updates it on events read from a
+// durable JetStream consumer. An event is acknowledged only after the index
+// has been updated, so events published while the service is down are
+// delivered on start. As a safety net against lost events the index is also
+// rebuilt every ttl.
 type Iniziator struct {
 	log      *slog.Logger
 	searcher core.Searcher
 	stopCh   chan struct{}
 	ttl      time.Duration
 	nc       *nats.Conn
+	consumer jetstream.Consumer
 }
 
-func New(log *slog.Logger, searcher core.Searcher, ttl time.Duration, brokerAddress string) (*Iniziator, error) {
+func New(
+	log *slog.Logger, searcher core.Searcher, ttl time.Duration, brokerAddress string,
+	stream string, maxAge time.Duration, durable string, ackWait time.Duration,
+) (*Iniziator, error) {
 	nc, err := nats.Connect(brokerAddress)
 	if err != nil {
 		return &Iniziator{}, err
 	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return &Iniziator{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+	s, err := events.EnsureStream(ctx, js, stream, maxAge)
+	if err != nil {
+		nc.Close()
+		return &Iniziator{}, err
+	}
+	consumer, err := s.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: events.SubjectDBChanged,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       ackWait,
+		MaxDeliver:    -1,
+	})
+	if err != nil {
+		nc.Close()
+		return &Iniziator{}, err
+	}
+	log.Info("consuming db changed events", "stream", stream, "durable", durable)
+
 	return &Iniziator{
 		log:      log,
 		searcher: searcher,
 		stopCh:   make(chan struct{}),
 		ttl:      ttl,
 		nc:       nc,
+		consumer: consumer,
 	}, nil
 }
 
 func (i *Iniziator) Start(ctx context.Context) {
-	sub, err := i.nc.Subscribe("xkcd.db.updated", func(msg *nats.Msg) {
-		i.log.Info("received message", "data", msg.Data)
-		if err := i.searcher.UpdateIndex(ctx); err != nil {
-			i.log.Error("failed to rebuild index", "error", err)
-		}
+	cc, err := i.consumer.Consume(func(msg jetstream.Msg) {
+		i.handle(ctx, msg)
 	})
-
 	if err != nil {
 		i.log.Error("failed to subscribe db change event publisher", "error", err)
 		panic(err)
 	}
+	defer i.nc.Close()
+	defer cc.Stop()
+
+	if !i.build(ctx) {
+		return
+	}
 
+	// zero ttl disables periodic rebuilds
+	var tick <-chan time.Time
+	if i.ttl > 0 {
+		ticker := time.NewTicker(i.ttl)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
 	for {
 		select {
+		case <-tick:
+			i.log.Debug("rebuilding index by ttl")
+			if err := i.searcher.UpdateIndex(ctx); err != nil {
+				i.log.Error("failed to rebuild index by ttl", "error", err)
+			}
 		case <-i.stopCh:
 			i.log.Info("stopping index initiator due to Close call")
-			if err := sub.Unsubscribe(); err != nil {
-				i.log.Error("failed to unsubscribe db change event publisher")
-			}
 			return
 		case <-ctx.Done():
 			i.log.Info("stopping index initiator due to context cancellation")
-			if err := sub.Unsubscribe(); err != nil {
-				i.log.Error("failed to unsubscribe db change event publisher")
-			}
 			return
 		}
 	}
 }
 
+// build builds the index retrying failures, db may still be starting. It
+// returns false if the iniziator has been stopped before the index has been
+// built.
+func (i *Iniziator) build(ctx context.Context) bool {
+	delay := buildMinDelay
+	for {
+		err := i.searcher.UpdateIndex(ctx)
+		if err == nil {
+			return true
+		}
+		i.log.Warn("failed to build index, retrying", "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-i.stopCh:
+			timer.Stop()
+			i.log.Info("stopping index initiator due to Close call")
+			return false
+		case <-ctx.Done():
+			timer.Stop()
+			i.log.Info("stopping index initiator due to context cancellation")
+			return false
+		}
+		delay = min(2*delay, buildMaxDelay)
+	}
+}
+
+// handle applies the event to the index and acknowledges it. Events listing
+// changed comics are applied as deltas, on other events the index is rebuilt
+// from scratch. If the index fails to update, the event is delivered again
+// later.
+func (i *Iniziator) handle(ctx context.Context, msg jetstream.Msg) {
+	event, err := events.Decode(msg.Data())
+	if err != nil {
+		// the index is rebuilt anyway, so changes are not missed
+		i.log.Warn("failed to decode db changed event", "data", string(msg.Data()), "error", err)
+	}
+	i.log.Info("received db changed event", "type", event.Type, "job", event.JobID,
+		"added", len(event.Added), "updated", len(event.Updated), "deleted", len(event.Deleted))
+
+	// Every event is applied, a delta of a pending event does not cover
+	// changes of earlier ones.
+	if err == nil && event.Partial() {
+		err = i.searcher.UpdateIndexDelta(ctx, core.IndexDelta{
+			Added:   event.Added,
+			Updated: event.Updated,
+			Deleted: event.Deleted,
+		})
+	} else {
+		err = i.searcher.UpdateIndex(ctx)
+	}
+	if err != nil {
+		i.log.Error("failed to update index", "error", err)
+		if err := msg.NakWithDelay(nakDelay); err != nil {
+			i.log.Error("failed to nak db changed event", "error", err)
+		}
+		return
+	}
+	i.ack(msg)
+}
+
+func (i *Iniziator) ack(msg jetstream.Msg) {
+	if err := msg.Ack(); err != nil {
+		i.log.Error("failed to ack db changed event", "error", err)
+	}
+}
+
 func (i *Iniziator) Stop() {
 	close(i.stopCh)
 }
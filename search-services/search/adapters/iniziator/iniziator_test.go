@@ -0,0 +1,183 @@
+package iniziator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/events"
+	"yadro.com/course/search/core"
+)
+
+type fakeSearcher struct {
+	core.Searcher
+	err error
+	// failures is the number of first updates failing with errStarting.
+	failures atomic.Int32
+	updates  chan struct{}
+	deltas   chan core.IndexDelta
+}
+
+var errStarting = errors.New("db is starting")
+
+func (f *fakeSearcher) UpdateIndex(context.Context) error {
+	f.updates <- struct{}{}
+	if f.failures.Add(-1) >= 0 {
+		return errStarting
+	}
+	return f.err
+}
+
+func (f *fakeSearcher) UpdateIndexDelta(_ context.Context, delta core.IndexDelta) error {
+	f.deltas <- delta
+	return f.err
+}
+
+func runServer(t *testing.T) string {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	go ns.Start()
+	require.True(t, ns.ReadyForConnections(5*time.Second))
+	t.Cleanup(ns.Shutdown)
+	return ns.ClientURL()
+}
+
+func publish(t *testing.T, url string, data []byte) {
+	t.Helper()
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	_, err = events.EnsureStream(context.Background(), js, "XKCD_DB", time.Hour)
+	require.NoError(t, err)
+	_, err = js.Publish(context.Background(), events.SubjectDBChanged, data)
+	require.NoError(t, err)
+}
+
+func start(t *testing.T, url string, searcher core.Searcher) *Iniziator {
+	t.Helper()
+	return startWithTTL(t, url, searcher, time.Minute)
+}
+
+func startWithTTL(t *testing.T, url string, searcher core.Searcher, ttl time.Duration) *Iniziator {
+	t.Helper()
+	i, err := New(slog.Default(), searcher, ttl, url, "XKCD_DB", time.Hour, "search", time.Minute)
+	require.NoError(t, err)
+	go i.Start(context.Background())
+	t.Cleanup(i.Stop)
+	return i
+}
+
+func waitUpdate(t *testing.T, searcher *fakeSearcher) {
+	t.Helper()
+	select {
+	case <-searcher.updates:
+	case <-time.After(5 * time.Second):
+		t.Fatal("index has not been rebuilt")
+	}
+}
+
+func waitDelta(t *testing.T, searcher *fakeSearcher) core.IndexDelta {
+	t.Helper()
+	select {
+	case delta := <-searcher.deltas:
+		return delta
+	case <-time.After(5 * time.Second):
+		t.Fatal("delta has not been applied")
+		return core.IndexDelta{}
+	}
+}
+
+func TestIniziator_DeliversEventsPublishedBeforeStart(t *testing.T) {
+	url := runServer(t)
+	data, err := events.Encode(events.DBChanged{Type: events.TypeComicsStored, Added: []int{1}})
+	require.NoError(t, err)
+	publish(t, url, data)
+	publish(t, url, []byte("XKCD DB has been updated"))
+
+	searcher := &fakeSearcher{updates: make(chan struct{}, 3), deltas: make(chan core.IndexDelta, 3)}
+	i := start(t, url, searcher)
+
+	// the index is built on start, the stored comics are applied as a delta
+	// and the legacy event rebuilds the index
+	waitUpdate(t, searcher)
+	assert.Equal(t, core.IndexDelta{Added: []int{1}}, waitDelta(t, searcher))
+	waitUpdate(t, searcher)
+	require.Eventually(t, func() bool {
+		info, err := i.consumer.Info(context.Background())
+		return err == nil && info.AckFloor.Consumer == 2 && info.NumAckPending == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, searcher.updates)
+	assert.Empty(t, searcher.deltas)
+}
+
+func TestIniziator_AppliesDeletedComics(t *testing.T) {
+	url := runServer(t)
+	searcher := &fakeSearcher{updates: make(chan struct{}, 3), deltas: make(chan core.IndexDelta, 3)}
+	start(t, url, searcher)
+	waitUpdate(t, searcher)
+
+	data, err := events.Encode(events.DBChanged{Type: events.TypeComicsDeleted, Deleted: []int{5, 7}})
+	require.NoError(t, err)
+	publish(t, url, data)
+
+	assert.Equal(t, core.IndexDelta{Deleted: []int{5, 7}}, waitDelta(t, searcher))
+	assert.Empty(t, searcher.updates)
+}
+
+func TestIniziator_KeepsEventIfIndexFailed(t *testing.T) {
+	url := runServer(t)
+	searcher := &fakeSearcher{err: errors.New("db is down"), updates: make(chan struct{}, 10)}
+	i := start(t, url, searcher)
+
+	data, err := events.Encode(events.DBChanged{Type: events.TypeDBDropped})
+	require.NoError(t, err)
+	publish(t, url, data)
+
+	waitUpdate(t, searcher)
+	require.Eventually(t, func() bool {
+		info, err := i.consumer.Info(context.Background())
+		return err == nil && info.NumAckPending == 1 && info.AckFloor.Consumer == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestIniziator_BuildsIndexOnStart(t *testing.T) {
+	url := runServer(t)
+	searcher := &fakeSearcher{updates: make(chan struct{}, 10)}
+	searcher.failures.Store(1)
+	start(t, url, searcher)
+
+	// the first attempt fails while db is starting, the second one is made
+	// after buildMinDelay
+	waitUpdate(t, searcher)
+	waitUpdate(t, searcher)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, searcher.updates)
+}
+
+func TestIniziator_RebuildsIndexByTTL(t *testing.T) {
+	url := runServer(t)
+	searcher := &fakeSearcher{updates: make(chan struct{}, 10)}
+	startWithTTL(t, url, searcher, 20*time.Millisecond)
+
+	for range 3 {
+		waitUpdate(t, searcher)
+	}
+}
@@ -7,13 +7,27 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+type Stream struct {
+	Name    string        `yaml:"name" env:"STREAM_NAME" env-default:"XKCD_DB"`
+	MaxAge  time.Duration `yaml:"max_age" env:"STREAM_MAX_AGE" env-default:"24h"`
+	Durable string        `yaml:"durable" env:"STREAM_DURABLE" env-default:"search"`
+	AckWait time.Duration `yaml:"ack_wait" env:"STREAM_ACK_WAIT" env-default:"1m"`
+}
+
+type BM25 struct {
+	K1 float64 `yaml:"k1" env:"BM25_K1" env-default:"1.2"`
+	B  float64 `yaml:"b" env:"BM25_B" env-default:"0.75"`
+}
+
 type Config struct {
 	LogLevel      string        `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
 	Address       string        `yaml:"search_address" env:"SEARCH_ADDRESS" env-default:"localhost:80"`
 	WordsAddress  string        `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
 	DBAddress     string        `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
-	TtlInit       time.Duration `yaml:"ttl_init" env:"INDEX_TTL" env-default:"20s"`
+	TtlInit       time.Duration `yaml:"ttl_init" env:"INDEX_TTL" env-default:"24h"`
 	BrokerAddress string        `yaml:"broker_address" env:"BROKER_ADDRESS" env-default:"nats://localhost:4222"`
+	Stream        Stream        `yaml:"stream"`
+	BM25          BM25          `yaml:"bm25"`
 }
 
 func MustLoad(configPath string) Config {
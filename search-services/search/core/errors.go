@@ -0,0 +1,7 @@
+package core
+
+import "errors"
+
+var ErrBadArguments = errors.New("arguments are not acceptable")
+var ErrNotFound = errors.New("resource is not found")
+var ErrNotReady = errors.New("index is not built yet")
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"maps"
+	"math"
+	"time"
+)
+
+// index is a snapshot of the search index. It is never changed after it has
+// been published, UpdateIndex builds a new one and replaces the old one with
+// an atomic swap, so searches read a complete snapshot without locking.
+type index struct {
+	words   map[string]map[int]int // word -> id -> occurrences of the word in comics
+	comics  map[int][]string       // id -> words, to remove comics from words
+	lengths map[int]int            // id -> number of words in comics
+	// total is the sum of lengths, it gives the average length of comics.
+	total int
+	// generation counts builds of the index, the first one has generation 1.
+	generation uint64
+	builtAt    time.Time
+}
+
+func newIndex(words map[string]map[int]int) *index {
+	comics := make(map[int][]string)
+	lengths := make(map[int]int)
+	total := 0
+	for word, ids := range words {
+		for id, count := range ids {
+			comics[id] = append(comics[id], word)
+			lengths[id] += count
+			total += count
+		}
+	}
+	return &index{words: words, comics: comics, lengths: lengths, total: total, builtAt: time.Now()}
+}
+
+// score ranks comics containing any of the words with BM25.
+func (ix *index) score(words []string, bm25 BM25Policy) map[int]float64 {
+	scores := make(map[int]float64)
+	n := float64(len(ix.lengths))
+	if n == 0 {
+		return scores
+	}
+	avgLength := float64(ix.total) / n
+	for _, word := range words {
+		ids, ok := ix.words[word]
+		if !ok {
+			continue
+		}
+		matched := float64(len(ids))
+		idf := math.Log(1 + (n-matched+0.5)/(matched+0.5))
+		for id, count := range ids {
+			tf := float64(count)
+			norm := 1 - bm25.B
+			if avgLength > 0 {
+				norm += bm25.B * float64(ix.lengths[id]) / avgLength
+			}
+			scores[id] += idf * tf * (bm25.K1 + 1) / (tf + bm25.K1*norm)
+		}
+	}
+	return scores
+}
+
+// UpdateIndex builds the index from scratch and replaces the old one, so
+// comics deleted from db disappear from search results. Searches running
+// during the build keep using the old index.
+func (s *Service) UpdateIndex(ctx context.Context) error {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	return s.build(ctx)
+}
+
+func (s *Service) build(ctx context.Context) error {
+	comics, err := s.db.FindAll(ctx)
+	if err != nil {
+		s.log.Error("Failed to update index", "error", err)
+		return err
+	}
+
+	words := make(map[string]map[int]int, len(comics.Comics))
+	for _, comics := range comics.Comics {
+		if _, ok := words[comics.Word]; !ok {
+			words[comics.Word] = make(map[int]int)
+		}
+		tmp := words[comics.Word]
+
+		for _, id := range comics.Comics_ids {
+			tmp[id]++
+		}
+	}
+
+	s.publish(newIndex(words))
+	return nil
+}
+
+// UpdateIndexDelta applies changes of comics to the index instead of
+// rebuilding it. Words of added and updated comics are loaded from db, so
+// applying the same delta twice or an outdated delta leaves the index as db
+// is now. Until the index has been built, it is built from scratch.
+func (s *Service) UpdateIndexDelta(ctx context.Context, delta IndexDelta) error {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	prev := s.index.Load()
+	if prev == nil {
+		return s.build(ctx)
+	}
+
+	changed := append(append([]int{}, delta.Added...), delta.Updated...)
+	loaded := &IndexInfo{}
+	if len(changed) > 0 {
+		var err error
+		if loaded, err = s.db.FindByIds(ctx, changed); err != nil {
+			s.log.Error("Failed to update index", "error", err)
+			return err
+		}
+	}
+
+	// Word sets are shared with the previous snapshot, only sets of words
+	// of changed comics are copied before they are changed.
+	words := maps.Clone(prev.words)
+	comics := maps.Clone(prev.comics)
+	lengths := maps.Clone(prev.lengths)
+	total := prev.total
+	copied := make(map[string]bool)
+	ids := func(word string) map[int]int {
+		if !copied[word] {
+			words[word] = maps.Clone(words[word])
+			if words[word] == nil {
+				words[word] = make(map[int]int)
+			}
+			copied[word] = true
+		}
+		return words[word]
+	}
+
+	for _, id := range append(changed, delta.Deleted...) {
+		for _, word := range comics[id] {
+			delete(ids(word), id)
+		}
+		total -= lengths[id]
+		delete(comics, id)
+		delete(lengths, id)
+	}
+	for _, one := range loaded.Comics {
+		set := ids(one.Word)
+		for _, id := range one.Comics_ids {
+			set[id]++
+			if set[id] == 1 {
+				comics[id] = append(comics[id], one.Word)
+			}
+			lengths[id]++
+			total++
+		}
+	}
+	for word := range copied {
+		if len(words[word]) == 0 {
+			delete(words, word)
+		}
+	}
+
+	s.log.Info("Index delta has been applied",
+		"added", len(delta.Added), "updated", len(delta.Updated), "deleted", len(delta.Deleted))
+	s.publish(&index{
+		words: words, comics: comics, lengths: lengths, total: total, builtAt: time.Now(),
+	})
+	return nil
+}
+
+// publish numbers the next generation of the index and replaces the current
+// one with it. Generation and build time of the current index are reported
+// by IndexStats, so operators can tell which index serves searches.
+func (s *Service) publish(next *index) {
+	next.generation = 1
+	if prev := s.index.Load(); prev != nil {
+		next.generation = prev.generation + 1
+	}
+	s.index.Store(next)
+	s.log.Info("Index has been published", "generation", next.generation, "built_at", next.builtAt,
+		"words", len(next.words), "comics", len(next.lengths))
+}
+
+// Ready returns ErrNotReady until the index has been built.
+func (s *Service) Ready(_ context.Context) error {
+	if s.index.Load() == nil {
+		return ErrNotReady
+	}
+	return nil
+}
+
+// IndexStats returns generation, build time and size of the index serving
+// searches, ErrNotReady until the index has been built.
+func (s *Service) IndexStats(_ context.Context) (IndexStats, error) {
+	index := s.index.Load()
+	if index == nil {
+		return IndexStats{}, ErrNotReady
+	}
+	return IndexStats{
+		Generation: index.generation,
+		BuiltAt:    index.builtAt,
+		Words:      len(index.words),
+		Comics:     len(index.lengths),
+	}, nil
+}
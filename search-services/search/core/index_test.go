@@ -0,0 +1,278 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// swappingDB serves two versions of the index by turns, comics 1 and 2 match
+// both words in the first one and comics 3 matches them in the second one.
+type swappingDB struct {
+	DB
+	builds atomic.Int64
+}
+
+func (d *swappingDB) FindAll(context.Context) (*IndexInfo, error) {
+	ids := []int{1, 2}
+	if d.builds.Add(1)%2 == 0 {
+		ids = []int{3}
+	}
+	return &IndexInfo{Comics: []IndexInfoOne{
+		{Word: "linux", Comics_ids: ids},
+		{Word: "forever", Comics_ids: ids},
+	}}, nil
+}
+
+func (d *swappingDB) GetById(_ context.Context, id int) (*Comics, error) {
+	return &Comics{ID: id}, nil
+}
+
+type splitWords struct{}
+
+func (splitWords) Norm(context.Context, string) ([]string, error) {
+	return []string{"linux", "forever"}, nil
+}
+
+func TestService_SearchIndex_ConcurrentRebuild(t *testing.T) {
+	ctx := context.Background()
+	db := &swappingDB{}
+	service, err := NewService(slog.Default(), db, splitWords{}, bm25)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(ctx))
+
+	const rebuilds = 200
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				reply, err := service.SearchIndex(ctx, SearchRequest{Phrase: "linux forever", Limit: 10})
+				if !assert.NoError(t, err) {
+					return
+				}
+				ids := make([]int, len(reply.Comics))
+				for i, comics := range reply.Comics {
+					ids[i] = comics.ID
+				}
+				// a search never sees a half built index
+				if !slices.Equal(ids, []int{1, 2}) && !slices.Equal(ids, []int{3}) {
+					assert.Fail(t, "search has seen a mix of two indexes", "ids %v", ids)
+					return
+				}
+			}
+		}()
+	}
+
+	var rebuilders sync.WaitGroup
+	for range 4 {
+		rebuilders.Add(1)
+		go func() {
+			defer rebuilders.Done()
+			for range rebuilds / 4 {
+				assert.NoError(t, service.UpdateIndex(ctx))
+			}
+		}()
+	}
+	rebuilders.Wait()
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, uint64(rebuilds+1), service.index.Load().generation)
+}
+
+func TestService_UpdateIndex_KeepsOldIndexOnError(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	db.On("FindAll", ctx).Return(&IndexInfo{
+		Comics: []IndexInfoOne{{Word: "test", Comics_ids: []int{1}}},
+	}, nil).Once()
+	db.On("FindAll", ctx).Return(nil, assert.AnError).Once()
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateIndex(ctx))
+	built := service.index.Load()
+
+	assert.ErrorIs(t, service.UpdateIndex(ctx), assert.AnError)
+	assert.Same(t, built, service.index.Load())
+	assert.NoError(t, service.Ready(ctx))
+}
+
+func TestService_IndexStats(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	db.On("FindAll", ctx).Return(&IndexInfo{Comics: []IndexInfoOne{
+		{Word: "test", Comics_ids: []int{1, 2}},
+		{Word: "hello", Comics_ids: []int{1}},
+	}}, nil)
+	db.On("FindByIds", ctx, []int{3}).Return(&IndexInfo{Comics: []IndexInfoOne{
+		{Word: "world", Comics_ids: []int{3}},
+	}}, nil)
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	_, err = service.IndexStats(ctx)
+	assert.ErrorIs(t, err, ErrNotReady)
+
+	before := time.Now()
+	require.NoError(t, service.UpdateIndex(ctx))
+	first, err := service.IndexStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), first.Generation)
+	assert.False(t, first.BuiltAt.Before(before))
+	assert.Equal(t, 2, first.Words)
+	assert.Equal(t, 2, first.Comics)
+
+	require.NoError(t, service.UpdateIndexDelta(ctx, IndexDelta{Added: []int{3}}))
+	second, err := service.IndexStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), second.Generation)
+	assert.False(t, second.BuiltAt.Before(first.BuiltAt))
+	assert.Equal(t, 3, second.Words)
+	assert.Equal(t, 3, second.Comics)
+}
+
+func TestService_UpdateIndexDelta(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	db.On("FindAll", ctx).Return(&IndexInfo{Comics: []IndexInfoOne{
+		{Word: "test", Comics_ids: []int{1, 2}},
+		{Word: "hello", Comics_ids: []int{1, 4}},
+	}}, nil).Once()
+	db.On("FindByIds", ctx, []int{3, 1}).Return(&IndexInfo{Comics: []IndexInfoOne{
+		{Word: "hello", Comics_ids: []int{3}},
+		{Word: "world", Comics_ids: []int{1, 3}},
+	}}, nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(ctx))
+	built := service.index.Load()
+
+	require.NoError(t, service.UpdateIndexDelta(ctx, IndexDelta{Added: []int{3}, Updated: []int{1}, Deleted: []int{2}}))
+	db.AssertExpectations(t)
+
+	index := service.index.Load()
+	assert.Equal(t, map[string]map[int]int{
+		"hello": {3: 1, 4: 1},
+		"world": {1: 1, 3: 1},
+	}, index.words)
+	assert.ElementsMatch(t, []string{"world"}, index.comics[1])
+	assert.NotContains(t, index.comics, 2)
+	assert.Equal(t, map[int]int{1: 1, 3: 2, 4: 1}, index.lengths)
+	assert.Equal(t, 4, index.total)
+	assert.Equal(t, uint64(2), index.generation)
+
+	// the previous snapshot is left as it was for searches still reading it
+	assert.Equal(t, map[string]map[int]int{
+		"test":  {1: 1, 2: 1},
+		"hello": {1: 1, 4: 1},
+	}, built.words)
+}
+
+func TestService_UpdateIndexDelta_BuildsIndexIfNotReady(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	db.On("FindAll", ctx).Return(&IndexInfo{Comics: []IndexInfoOne{
+		{Word: "test", Comics_ids: []int{1}},
+	}}, nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateIndexDelta(ctx, IndexDelta{Added: []int{1}}))
+	db.AssertExpectations(t)
+	db.AssertNotCalled(t, "FindByIds", ctx, []int{1})
+	assert.Equal(t, map[int]int{1: 1}, service.index.Load().words["test"])
+}
+
+func TestService_UpdateIndexDelta_KeepsOldIndexOnError(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	db.On("FindByIds", ctx, []int{1}).Return(nil, assert.AnError).Once()
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+	setIndex(service, map[string]map[int]int{"test": {1: 1}})
+	built := service.index.Load()
+
+	assert.ErrorIs(t, service.UpdateIndexDelta(ctx, IndexDelta{Updated: []int{1}}), assert.AnError)
+	assert.Same(t, built, service.index.Load())
+}
+
+// fieldWords normalizes a phrase of already normalized words.
+type fieldWords struct{}
+
+func (fieldWords) Norm(_ context.Context, phrase string) ([]string, error) {
+	return strings.Fields(phrase), nil
+}
+
+// TestService_SearchIndex_Relevance ranks a fixed corpus, where comics about
+// a word have to outrank comics mentioning it in passing.
+func TestService_SearchIndex_Relevance(t *testing.T) {
+	var fixture struct {
+		Comics []struct {
+			ID    int      `json:"id"`
+			Words []string `json:"words"`
+		} `json:"comics"`
+		Queries []struct {
+			Query    []string `json:"query"`
+			Expected []int    `json:"expected"`
+		} `json:"queries"`
+	}
+	data, err := os.ReadFile("testdata/relevance.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &fixture))
+
+	ctx := context.Background()
+	db := &MockDB{}
+	ids := make(map[string][]int)
+	for _, comics := range fixture.Comics {
+		for _, word := range comics.Words {
+			ids[word] = append(ids[word], comics.ID)
+		}
+		db.On("GetById", ctx, comics.ID).Return(&Comics{ID: comics.ID}, nil)
+	}
+	info := &IndexInfo{}
+	for word, ids := range ids {
+		info.Comics = append(info.Comics, IndexInfoOne{Word: word, Comics_ids: ids})
+	}
+	db.On("FindAll", ctx).Return(info, nil)
+
+	service, err := NewService(slog.Default(), db, fieldWords{}, bm25)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(ctx))
+
+	for _, query := range fixture.Queries {
+		phrase := strings.Join(query.Query, " ")
+		t.Run(phrase, func(t *testing.T) {
+			reply, err := service.SearchIndex(ctx, SearchRequest{Phrase: phrase, Limit: 10})
+			require.NoError(t, err)
+			got := make([]int, len(reply.Comics))
+			for i, comics := range reply.Comics {
+				got[i] = comics.ID
+			}
+			assert.Equal(t, query.Expected, got)
+		})
+	}
+}
@@ -1,10 +1,25 @@
 package core
 
+import "time"
+
 type Comics struct {
-	ID  int
-	URL string
+	ID         int
+	URL        string
+	Title      string
+	SafeTitle  string
+	Alt        string
+	Transcript string
+	Published  time.Time
+	// Score, Matched and Missing are set for search results only. Matched and
+	// Missing list normalized words of the phrase found and not found in
+	// comics.
+	Score   float64
+	Matched []string
+	Missing []string
 }
 
+// IndexInfoOne lists comics containing the word, id of comics is repeated
+// once per occurrence of the word in it.
 type IndexInfoOne struct {
 	Word       string
 	Comics_ids []int
@@ -14,6 +29,30 @@ type IndexInfo struct {
 	Comics []IndexInfoOne
 }
 
+// BM25Policy holds parameters of BM25 ranking of index search. K1 tells how
+// fast the score saturates with occurrences of a word and B how much long
+// comics are penalized, zero B ignores length of comics.
+type BM25Policy struct {
+	K1 float64
+	B  float64
+}
+
+// IndexDelta lists comics changed since the index has been built.
+type IndexDelta struct {
+	Added   []int
+	Updated []int
+	Deleted []int
+}
+
+// IndexStats describes the index serving searches, Generation and BuiltAt
+// tell how fresh it is.
+type IndexStats struct {
+	Generation uint64
+	BuiltAt    time.Time
+	Words      int
+	Comics     int
+}
+
 type SearchReply struct {
 	Comics []Comics
 }
@@ -22,3 +61,21 @@ type SearchRequest struct {
 	Limit  int
 	Phrase string
 }
+
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+type ListRequest struct {
+	Offset int
+	Limit  int
+	Order  Order
+}
+
+type ListReply struct {
+	Comics []Comics
+	Total  int
+}
@@ -8,12 +8,20 @@ type Searcher interface {
 	Search(context context.Context, request SearchRequest) (*SearchReply, error)
 	SearchIndex(context context.Context, request SearchRequest) (*SearchReply, error)
 	UpdateIndex(context context.Context) error
+	UpdateIndexDelta(context context.Context, delta IndexDelta) error
+	Ready(context context.Context) error
+	IndexStats(context context.Context) (IndexStats, error)
+	Comics(context context.Context, id int) (*Comics, error)
+	List(context context.Context, request ListRequest) (*ListReply, error)
 }
 
 type DB interface {
 	Find(context context.Context, words []string, limit int) (*SearchReply, error)
 	FindAll(context context.Context) (*IndexInfo, error)
+	FindByIds(context context.Context, ids []int) (*IndexInfo, error)
 	GetById(context context.Context, id int) (*Comics, error)
+	List(context context.Context, offset, limit int, order Order) ([]Comics, error)
+	Count(context context.Context) (int, error)
 }
 
 type Words interface {
@@ -2,47 +2,38 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
 type Service struct {
 	log   *slog.Logger
 	db    DB
 	words Words
-	index map[string]map[int]bool // index: word -> id
-}
-
-func (s *Service) UpdateIndex(ctx context.Context) error {
-	comics, err := s.db.FindAll(ctx)
-	if err != nil {
-		s.log.Error("Failed to update index", "error", err)
-		return err
-	}
-
-	for _, comics := range comics.Comics {
-		if _, ok := s.index[comics.Word]; !ok {
-			s.index[comics.Word] = make(map[int]bool)
-		}
-		tmp := s.index[comics.Word]
-
-		for _, index := range comics.Comics_ids {
-			tmp[index] = true
-		}
+	bm25  BM25Policy
 
-		s.index[comics.Word] = tmp
-	}
-	return nil
+	// index is nil until the index has been built for the first time.
+	index atomic.Pointer[index]
+	// buildMu makes rebuilds run one by one, so generations grow in order.
+	buildMu sync.Mutex
 }
 
 func NewService(
-	log *slog.Logger, db DB, words Words,
+	log *slog.Logger, db DB, words Words, bm25 BM25Policy,
 ) (*Service, error) {
+	if bm25.K1 < 0 || bm25.B < 0 || bm25.B > 1 {
+		return nil, fmt.Errorf("wrong bm25 policy specified: %+v", bm25)
+	}
 	return &Service{
 		log:   log,
 		db:    db,
 		words: words,
-		index: make(map[string]map[int]bool),
+		bm25:  bm25,
 	}, nil
 }
 
@@ -56,10 +47,41 @@ func (s *Service) Search(ctx context.Context, request SearchRequest) (*SearchRep
 	if err != nil {
 		return &SearchReply{}, err
 	}
+	// comics found in db are scored by the number of matched words
+	for i := range reply.Comics {
+		comics := &reply.Comics[i]
+		comics.Matched, comics.Missing = splitTerms(words, func(word string) bool {
+			return slices.Contains(comics.Matched, word)
+		})
+		comics.Score = float64(len(comics.Matched))
+	}
 	return reply, nil
 }
 
+// splitTerms splits words of the phrase into found in comics and missing
+// ones, both sorted.
+func splitTerms(words []string, found func(string) bool) (matched, missing []string) {
+	for _, word := range words {
+		if found(word) {
+			matched = append(matched, word)
+		} else {
+			missing = append(missing, word)
+		}
+	}
+	slices.Sort(matched)
+	slices.Sort(missing)
+	return matched, missing
+}
+
+// SearchIndex finds comics using the index and ranks them with BM25, comics
+// with equal scores are ordered by id. Until the index has been built,
+// ErrNotReady is returned instead of empty results.
 func (s *Service) SearchIndex(ctx context.Context, request SearchRequest) (*SearchReply, error) {
+	index := s.index.Load()
+	if index == nil {
+		return &SearchReply{}, ErrNotReady
+	}
+
 	// Normilize
 	words, err := s.words.Norm(ctx, request.Phrase)
 	if err != nil {
@@ -67,22 +89,14 @@ func (s *Service) SearchIndex(ctx context.Context, request SearchRequest) (*Sear
 	}
 
 	// Find relevant comics id
-	comicsMatches := make(map[int]int)
-	for _, word := range words {
-		if comics, ok := s.index[word]; ok {
-			for comicId := range comics {
-				comicsMatches[comicId]++
-			}
-		}
-	}
-
+	comicsMatches := index.score(words, s.bm25)
 	if len(comicsMatches) == 0 {
 		return &SearchReply{}, nil
 	}
 
 	type ComicScore struct {
 		ID    int
-		Score int
+		Score float64
 	}
 
 	var scoredComics []ComicScore
@@ -105,12 +119,54 @@ func (s *Service) SearchIndex(ctx context.Context, request SearchRequest) (*Sear
 
 	// Find needed ids in db
 	reply := make([]Comics, 0)
-	for _, index := range result {
-		comicsRaw, err := s.db.GetById(ctx, index)
+	for _, id := range result {
+		comicsRaw, err := s.db.GetById(ctx, id)
 		if err == nil {
+			comicsRaw.Score = comicsMatches[id]
+			comicsRaw.Matched, comicsRaw.Missing = splitTerms(words, func(word string) bool {
+				return index.words[word][id] > 0
+			})
 			reply = append(reply, *comicsRaw)
 		}
 	}
 
 	return &SearchReply{Comics: reply}, nil
 }
+
+func (s *Service) Comics(ctx context.Context, id int) (*Comics, error) {
+	if id <= 0 {
+		return &Comics{}, ErrBadArguments
+	}
+	comic, err := s.db.GetById(ctx, id)
+	if err != nil {
+		s.log.Error("Failed to get comics "+strconv.Itoa(id), "error", err)
+		return &Comics{}, err
+	}
+	return comic, nil
+}
+
+func (s *Service) List(ctx context.Context, request ListRequest) (*ListReply, error) {
+	if request.Offset < 0 || request.Limit <= 0 {
+		return &ListReply{}, ErrBadArguments
+	}
+	switch request.Order {
+	case "":
+		request.Order = OrderAsc
+	case OrderAsc, OrderDesc:
+	default:
+		return &ListReply{}, ErrBadArguments
+	}
+
+	total, err := s.db.Count(ctx)
+	if err != nil {
+		s.log.Error("Failed to count comics", "error", err)
+		return &ListReply{}, err
+	}
+
+	comics, err := s.db.List(ctx, request.Offset, request.Limit, request.Order)
+	if err != nil {
+		s.log.Error("Failed to list comics", "error", err)
+		return &ListReply{}, err
+	}
+	return &ListReply{Comics: comics, Total: total}, nil
+}
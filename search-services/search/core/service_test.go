@@ -32,6 +32,14 @@ func (m *MockDB) FindAll(ctx context.Context) (*IndexInfo, error) {
 	return args.Get(0).(*IndexInfo), args.Error(1)
 }
 
+func (m *MockDB) FindByIds(ctx context.Context, ids []int) (*IndexInfo, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*IndexInfo), args.Error(1)
+}
+
 func (m *MockDB) GetById(ctx context.Context, id int) (*Comics, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -40,6 +48,19 @@ func (m *MockDB) GetById(ctx context.Context, id int) (*Comics, error) {
 	return args.Get(0).(*Comics), args.Error(1)
 }
 
+func (m *MockDB) List(ctx context.Context, offset, limit int, order Order) ([]Comics, error) {
+	args := m.Called(ctx, offset, limit, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Comics), args.Error(1)
+}
+
+func (m *MockDB) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 type MockWords struct {
 	mock.Mock
 }
@@ -54,15 +75,29 @@ func TestNewService(t *testing.T) {
 	db := &MockDB{}
 	words := &MockWords{}
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, service)
 	assert.Equal(t, log, service.log)
 	assert.Equal(t, db, service.db)
 	assert.Equal(t, words, service.words)
-	assert.NotNil(t, service.index)
-	assert.Empty(t, service.index)
+	assert.Equal(t, bm25, service.bm25)
+	assert.Nil(t, service.index.Load())
+}
+
+func TestNewService_BadBM25Policy(t *testing.T) {
+	for _, policy := range []BM25Policy{{K1: -1, B: 0.75}, {K1: 1.2, B: -0.1}, {K1: 1.2, B: 1.5}} {
+		_, err := NewService(slog.Default(), &MockDB{}, &MockWords{}, policy)
+		assert.Error(t, err, "%+v", policy)
+	}
+}
+
+var bm25 = BM25Policy{K1: 1.2, B: 0.75}
+
+// setIndex publishes the index built of occurrences of words in comics.
+func setIndex(service *Service, words map[string]map[int]int) {
+	service.publish(newIndex(words))
 }
 
 func TestService_UpdateIndex_Success(t *testing.T) {
@@ -86,15 +121,20 @@ func TestService_UpdateIndex_Success(t *testing.T) {
 
 	db.On("FindAll", ctx).Return(indexData, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
+	assert.ErrorIs(t, service.Ready(ctx), ErrNotReady)
 	err = service.UpdateIndex(ctx)
 	assert.NoError(t, err)
+	assert.NoError(t, service.Ready(ctx))
 
-	assert.Len(t, service.index, 2)
-	assert.Equal(t, map[int]bool{1: true, 2: true, 3: true}, service.index["test"])
-	assert.Equal(t, map[int]bool{1: true, 4: true}, service.index["hello"])
+	index := service.index.Load()
+	assert.Len(t, index.words, 2)
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, index.words["test"])
+	assert.Equal(t, map[int]int{1: 1, 4: 1}, index.words["hello"])
+	assert.Equal(t, uint64(1), index.generation)
+	assert.False(t, index.builtAt.IsZero())
 
 	db.AssertExpectations(t)
 }
@@ -108,16 +148,37 @@ func TestService_UpdateIndex_DBError(t *testing.T) {
 	expectedErr := errors.New("db error")
 	db.On("FindAll", ctx).Return(nil, expectedErr)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
 	err = service.UpdateIndex(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
+	assert.ErrorIs(t, service.Ready(ctx), ErrNotReady)
 
 	db.AssertExpectations(t)
 }
 
+func TestService_UpdateIndex_DropsDeletedComics(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+
+	db.On("FindAll", ctx).Return(&IndexInfo{
+		Comics: []IndexInfoOne{{Word: "test", Comics_ids: []int{1, 2}}},
+	}, nil).Once()
+	db.On("FindAll", ctx).Return(&IndexInfo{}, nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateIndex(ctx))
+	assert.Len(t, service.index.Load().words, 1)
+
+	require.NoError(t, service.UpdateIndex(ctx))
+	assert.Empty(t, service.index.Load().words)
+	assert.Equal(t, uint64(2), service.index.Load().generation)
+}
+
 func TestService_Search_Success(t *testing.T) {
 	ctx := context.Background()
 	log := slog.Default()
@@ -132,21 +193,28 @@ func TestService_Search_Success(t *testing.T) {
 	normalizedWords := []string{"test", "search"}
 	expectedReply := &SearchReply{
 		Comics: []Comics{
-			{ID: 1, URL: "https://xkcd.com/1"},
-			{ID: 2, URL: "https://xkcd.com/2"},
+			{ID: 1, URL: "https://xkcd.com/1", Matched: []string{"search", "test"}},
+			{ID: 2, URL: "https://xkcd.com/2", Matched: []string{"test"}},
 		},
 	}
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 	db.On("Find", ctx, normalizedWords, request.Limit).Return(expectedReply, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
 	reply, err := service.Search(ctx, request)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedReply, reply)
 
+	assert.Equal(t, 2.0, reply.Comics[0].Score)
+	assert.Equal(t, []string{"search", "test"}, reply.Comics[0].Matched)
+	assert.Empty(t, reply.Comics[0].Missing)
+	assert.Equal(t, 1.0, reply.Comics[1].Score)
+	assert.Equal(t, []string{"test"}, reply.Comics[1].Matched)
+	assert.Equal(t, []string{"search"}, reply.Comics[1].Missing)
+
 	words.AssertExpectations(t)
 	db.AssertExpectations(t)
 }
@@ -165,7 +233,7 @@ func TestService_Search_NormalizationError(t *testing.T) {
 	expectedErr := errors.New("normalization error")
 	words.On("Norm", ctx, request.Phrase).Return([]string{}, expectedErr)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
 	reply, err := service.Search(ctx, request)
@@ -194,7 +262,7 @@ func TestService_Search_DBError(t *testing.T) {
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 	db.On("Find", ctx, normalizedWords, request.Limit).Return(nil, expectedErr)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
 	reply, err := service.Search(ctx, request)
@@ -221,12 +289,14 @@ func TestService_SearchIndex_Success(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{1: true, 2: true, 3: true}
-	service.index["hello"] = map[int]bool{1: true, 4: true}
-	service.index["world"] = map[int]bool{5: true}
+	setIndex(service, map[string]map[int]int{
+		"test":  {1: 1, 2: 1, 3: 1},
+		"hello": {1: 1, 4: 1},
+		"world": {5: 1},
+	})
 
 	comic1 := &Comics{ID: 1, URL: "https://xkcd.com/1"}
 	comic2 := &Comics{ID: 2, URL: "https://xkcd.com/2"}
@@ -242,15 +312,38 @@ func TestService_SearchIndex_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, reply.Comics, 4)
 
+	// hello is rarer than test, so comics 4 outranks comics 2 and 3
 	assert.Equal(t, 1, reply.Comics[0].ID)
-	assert.Equal(t, 2, reply.Comics[1].ID)
-	assert.Equal(t, 3, reply.Comics[2].ID)
-	assert.Equal(t, 4, reply.Comics[3].ID)
+	assert.Equal(t, 4, reply.Comics[1].ID)
+	assert.Equal(t, 2, reply.Comics[2].ID)
+	assert.Equal(t, 3, reply.Comics[3].ID)
+
+	assert.Greater(t, reply.Comics[0].Score, reply.Comics[1].Score)
+	assert.Greater(t, reply.Comics[1].Score, reply.Comics[2].Score)
+	assert.Equal(t, reply.Comics[2].Score, reply.Comics[3].Score)
+	assert.Equal(t, []string{"hello", "test"}, reply.Comics[0].Matched)
+	assert.Empty(t, reply.Comics[0].Missing)
+	assert.Equal(t, []string{"hello"}, reply.Comics[1].Matched)
+	assert.Equal(t, []string{"test"}, reply.Comics[1].Missing)
 
 	words.AssertExpectations(t)
 	db.AssertExpectations(t)
 }
 
+func TestService_SearchIndex_NotReady(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	words := &MockWords{}
+
+	service, err := NewService(slog.Default(), db, words, bm25)
+	require.NoError(t, err)
+
+	reply, err := service.SearchIndex(ctx, SearchRequest{Phrase: "test", Limit: 10})
+	assert.ErrorIs(t, err, ErrNotReady)
+	assert.Empty(t, reply.Comics)
+	words.AssertNotCalled(t, "Norm", mock.Anything, mock.Anything)
+}
+
 func TestService_SearchIndex_WithLimit(t *testing.T) {
 	ctx := context.Background()
 	log := slog.Default()
@@ -266,23 +359,25 @@ func TestService_SearchIndex_WithLimit(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{1: true, 2: true, 3: true}
-	service.index["hello"] = map[int]bool{1: true, 4: true}
+	setIndex(service, map[string]map[int]int{
+		"test":  {1: 1, 2: 1, 3: 1},
+		"hello": {1: 1, 4: 1},
+	})
 
 	comic1 := &Comics{ID: 1, URL: "https://xkcd.com/1"}
-	comic2 := &Comics{ID: 2, URL: "https://xkcd.com/2"}
+	comic4 := &Comics{ID: 4, URL: "https://xkcd.com/4"}
 
 	db.On("GetById", ctx, 1).Return(comic1, nil)
-	db.On("GetById", ctx, 2).Return(comic2, nil)
+	db.On("GetById", ctx, 4).Return(comic4, nil)
 
 	reply, err := service.SearchIndex(ctx, request)
 	assert.NoError(t, err)
 	assert.Len(t, reply.Comics, 2)
 	assert.Equal(t, 1, reply.Comics[0].ID)
-	assert.Equal(t, 2, reply.Comics[1].ID)
+	assert.Equal(t, 4, reply.Comics[1].ID)
 
 	words.AssertExpectations(t)
 	db.AssertExpectations(t)
@@ -303,10 +398,12 @@ func TestService_SearchIndex_NoResults(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{1: true}
+	setIndex(service, map[string]map[int]int{
+		"test": {1: 1},
+	})
 
 	reply, err := service.SearchIndex(ctx, request)
 	assert.NoError(t, err)
@@ -332,10 +429,12 @@ func TestService_SearchIndex_EmptyPhrase(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{1: true}
+	setIndex(service, map[string]map[int]int{
+		"test": {1: 1},
+	})
 
 	reply, err := service.SearchIndex(ctx, request)
 	assert.NoError(t, err)
@@ -361,10 +460,12 @@ func TestService_SearchIndex_GetByIdError(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{1: true, 2: true}
+	setIndex(service, map[string]map[int]int{
+		"test": {1: 1, 2: 1},
+	})
 
 	comic1 := &Comics{ID: 1, URL: "https://xkcd.com/1"}
 	expectedErr := errors.New("db error")
@@ -395,8 +496,9 @@ func TestService_SearchIndex_NormalizationError(t *testing.T) {
 	expectedErr := errors.New("normalization error")
 	words.On("Norm", ctx, request.Phrase).Return([]string{}, expectedErr)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
+	setIndex(service, map[string]map[int]int{})
 
 	reply, err := service.SearchIndex(ctx, request)
 	assert.Error(t, err)
@@ -422,16 +524,18 @@ func TestService_SearchIndex_TieBreaking(t *testing.T) {
 
 	words.On("Norm", ctx, request.Phrase).Return(normalizedWords, nil)
 
-	service, err := NewService(log, db, words)
+	service, err := NewService(log, db, words, bm25)
 	require.NoError(t, err)
 
-	service.index["test"] = map[int]bool{
-		5: true,
-		1: true,
-		3: true,
-		2: true,
-		4: true,
-	}
+	setIndex(service, map[string]map[int]int{
+		"test": {
+			5: 1,
+			1: 1,
+			3: 1,
+			2: 1,
+			4: 1,
+		},
+	})
 
 	for i := 1; i <= 5; i++ {
 		comic := &Comics{ID: i, URL: "https://xkcd.com/" + string(rune('0'+i))}
@@ -449,3 +553,89 @@ func TestService_SearchIndex_TieBreaking(t *testing.T) {
 	words.AssertExpectations(t)
 	db.AssertExpectations(t)
 }
+
+func TestService_Comics(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	words := &MockWords{}
+
+	comic := &Comics{ID: 1, URL: "https://xkcd.com/1", Title: "Barrel - Part 1"}
+	db.On("GetById", ctx, 1).Return(comic, nil)
+	db.On("GetById", ctx, 2).Return(nil, ErrNotFound)
+
+	service, err := NewService(slog.Default(), db, words, bm25)
+	require.NoError(t, err)
+
+	reply, err := service.Comics(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, comic, reply)
+
+	_, err = service.Comics(ctx, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = service.Comics(ctx, 0)
+	assert.ErrorIs(t, err, ErrBadArguments)
+
+	db.AssertExpectations(t)
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+	words := &MockWords{}
+
+	comics := []Comics{{ID: 3}, {ID: 4}}
+	db.On("Count", ctx).Return(10, nil)
+	db.On("List", ctx, 2, 2, OrderAsc).Return(comics, nil)
+	db.On("List", ctx, 0, 5, OrderDesc).Return([]Comics{}, nil)
+
+	service, err := NewService(slog.Default(), db, words, bm25)
+	require.NoError(t, err)
+
+	reply, err := service.List(ctx, ListRequest{Offset: 2, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListReply{Comics: comics, Total: 10}, reply)
+
+	reply, err = service.List(ctx, ListRequest{Limit: 5, Order: OrderDesc})
+	assert.NoError(t, err)
+	assert.Empty(t, reply.Comics)
+
+	db.AssertExpectations(t)
+}
+
+func TestService_List_BadArguments(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	requests := []ListRequest{
+		{Offset: -1, Limit: 10},
+		{Offset: 0, Limit: 0},
+		{Offset: 0, Limit: 10, Order: "random"},
+	}
+	for _, request := range requests {
+		_, err := service.List(ctx, request)
+		assert.ErrorIs(t, err, ErrBadArguments)
+	}
+
+	db.AssertNotCalled(t, "Count", ctx)
+}
+
+func TestService_List_DBError(t *testing.T) {
+	ctx := context.Background()
+	db := &MockDB{}
+
+	expectedErr := errors.New("db error")
+	db.On("Count", ctx).Return(0, expectedErr)
+
+	service, err := NewService(slog.Default(), db, &MockWords{}, bm25)
+	require.NoError(t, err)
+
+	reply, err := service.List(ctx, ListRequest{Limit: 10})
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, &ListReply{}, reply)
+
+	db.AssertNotCalled(t, "List", ctx, mock.Anything, mock.Anything, mock.Anything)
+}
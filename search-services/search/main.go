@@ -54,13 +54,16 @@ func run(cfg config.Config, log *slog.Logger) error {
 	}
 
 	// service
-	searcher, err := core.NewService(log, storage, words)
+	searcher, err := core.NewService(log, storage, words, core.BM25Policy{K1: cfg.BM25.K1, B: cfg.BM25.B})
 	if err != nil {
 		return fmt.Errorf("failed create Update service: %v", err)
 	}
 
 	// iniziator
-	iniziator, err := iniziator.New(log, searcher, cfg.TtlInit, cfg.BrokerAddress)
+	iniziator, err := iniziator.New(
+		log, searcher, cfg.TtlInit, cfg.BrokerAddress,
+		cfg.Stream.Name, cfg.Stream.MaxAge, cfg.Stream.Durable, cfg.Stream.AckWait,
+	)
 	if err != nil {
 		return fmt.Errorf("failed create Iniziator service: %v", err)
 	}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"yadro.com/course/update/core"
+)
+
+type failedRow struct {
+	ID         int       `db:"id"`
+	Stage      string    `db:"stage"`
+	ErrorClass string    `db:"error_class"`
+	Error      string    `db:"error"`
+	Attempts   int       `db:"attempts"`
+	FailedAt   time.Time `db:"failed_at"`
+}
+
+// AddFailed records the failure, attempts are summed up with the previous
+// failures of the same comics.
+func (db *DB) AddFailed(ctx context.Context, failed core.FailedComics) error {
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO failed_comics (id, stage, error_class, error, attempts, failed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			stage = EXCLUDED.stage,
+			error_class = EXCLUDED.error_class,
+			error = EXCLUDED.error,
+			attempts = failed_comics.attempts + EXCLUDED.attempts,
+			failed_at = EXCLUDED.failed_at
+	`, failed.ID, failed.Stage, failed.ErrorClass, failed.Error, failed.Attempts, failed.FailedAt)
+	if err != nil {
+		db.log.Error("Failed to record failure of "+strconv.Itoa(failed.ID)+" comics", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) ListFailed(ctx context.Context) ([]core.FailedComics, error) {
+	var rows []failedRow
+	err := db.conn.SelectContext(ctx, &rows, `
+		SELECT id, stage, error_class, error, attempts, failed_at
+		FROM failed_comics
+		ORDER BY id
+	`)
+	if err != nil {
+		db.log.Error("Failed to list failed comics", "error", err)
+		return nil, err
+	}
+	failed := make([]core.FailedComics, len(rows))
+	for i, row := range rows {
+		failed[i] = core.FailedComics{
+			ID:         row.ID,
+			Stage:      core.FailureStage(row.Stage),
+			ErrorClass: core.ErrorClass(row.ErrorClass),
+			Error:      row.Error,
+			Attempts:   row.Attempts,
+			FailedAt:   row.FailedAt,
+		}
+	}
+	return failed, nil
+}
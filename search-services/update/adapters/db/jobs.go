@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"yadro.com/course/update/core"
+)
+
+type jobRow struct {
+	ID          int64        `db:"id"`
+	Kind        string       `db:"kind"`
+	StartedAt   time.Time    `db:"started_at"`
+	FinishedAt  sql.NullTime `db:"finished_at"`
+	Outcome     string       `db:"outcome"`
+	Total       int          `db:"total"`
+	Fetched     int          `db:"fetched"`
+	Normalized  int          `db:"normalized"`
+	Stored      int          `db:"stored"`
+	Failed      int          `db:"failed"`
+	Changed     int          `db:"changed"`
+	FetchMS     int64        `db:"fetch_ms"`
+	NormalizeMS int64        `db:"normalize_ms"`
+	StoreMS     int64        `db:"store_ms"`
+	Error       string       `db:"error"`
+	TriggeredBy string       `db:"triggered_by"`
+}
+
+func (r jobRow) toCore() core.Job {
+	return core.Job{
+		ID:          r.ID,
+		Kind:        core.JobKind(r.Kind),
+		StartedAt:   r.StartedAt,
+		FinishedAt:  r.FinishedAt.Time,
+		Outcome:     core.JobOutcome(r.Outcome),
+		Total:       r.Total,
+		Fetched:     r.Fetched,
+		Normalized:  r.Normalized,
+		Stored:      r.Stored,
+		Failed:      r.Failed,
+		Changed:     r.Changed,
+		Timings:     r.timings(),
+		Error:       r.Error,
+		TriggeredBy: r.TriggeredBy,
+	}
+}
+
+// timings returns stage timings of the job kept in milliseconds.
+func (r jobRow) timings() core.StageTimings {
+	return core.StageTimings{
+		Fetch:     time.Duration(r.FetchMS) * time.Millisecond,
+		Normalize: time.Duration(r.NormalizeMS) * time.Millisecond,
+		Store:     time.Duration(r.StoreMS) * time.Millisecond,
+	}
+}
+
+const jobColumns = `id, kind, started_at, finished_at, outcome, total, fetched, normalized, stored, failed, changed,
+	fetch_ms, normalize_ms, store_ms, error, triggered_by`
+
+func (db *DB) CreateJob(ctx context.Context, job core.Job) (int64, error) {
+	var id int64
+	err := db.conn.GetContext(ctx, &id, `
+		INSERT INTO update_jobs (kind, started_at, outcome, triggered_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, job.Kind, job.StartedAt, job.Outcome, job.TriggeredBy)
+	if err != nil {
+		db.log.Error("Failed to create update job", "error", err)
+		return 0, err
+	}
+	db.log.Info("Update job " + strconv.FormatInt(id, 10) + " has been created")
+	return id, nil
+}
+
+func (db *DB) FinishJob(ctx context.Context, job core.Job) error {
+	_, err := db.conn.ExecContext(ctx, `
+		UPDATE update_jobs SET
+			finished_at = $2,
+			outcome = $3,
+			total = $4,
+			fetched = $5,
+			normalized = $6,
+			stored = $7,
+			failed = $8,
+			error = $9,
+			changed = $10,
+			fetch_ms = $11,
+			normalize_ms = $12,
+			store_ms = $13
+		WHERE id = $1
+	`, job.ID, job.FinishedAt, job.Outcome, job.Total, job.Fetched, job.Normalized,
+		job.Stored, job.Failed, job.Error, job.Changed, job.Timings.Fetch.Milliseconds(),
+		job.Timings.Normalize.Milliseconds(), job.Timings.Store.Milliseconds())
+	if err != nil {
+		db.log.Error("Failed to finish update job "+strconv.FormatInt(job.ID, 10), "error", err)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetJob(ctx context.Context, id int64) (core.Job, error) {
+	var row jobRow
+	err := db.conn.GetContext(ctx, &row, `SELECT `+jobColumns+` FROM update_jobs WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.Job{}, core.ErrNotFound
+		}
+		db.log.Error("Failed to get update job "+strconv.FormatInt(id, 10), "error", err)
+		return core.Job{}, err
+	}
+	return row.toCore(), nil
+}
+
+func (db *DB) ListJobs(ctx context.Context, limit, offset int) ([]core.Job, error) {
+	var rows []jobRow
+	err := db.conn.SelectContext(ctx, &rows,
+		`SELECT `+jobColumns+` FROM update_jobs ORDER BY id DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		db.log.Error("Failed to list update jobs", "error", err)
+		return nil, err
+	}
+	jobs := make([]core.Job, len(rows))
+	for i, row := range rows {
+		jobs[i] = row.toCore()
+	}
+	return jobs, nil
+}
+
+// InterruptJobs marks jobs left running by a previous process as failed.
+func (db *DB) InterruptJobs(ctx context.Context) error {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE update_jobs SET finished_at = now(), outcome = $1, error = 'interrupted by restart'
+		WHERE outcome = $2
+	`, core.JobFailed, core.JobRunning)
+	if err != nil {
+		db.log.Error("Failed to interrupt stale update jobs", "error", err)
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		db.log.Info("Stale update jobs have been interrupted", "count", n)
+	}
+	return nil
+}
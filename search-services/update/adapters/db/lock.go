@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"yadro.com/course/update/core"
+)
+
+// updateLockKey is the key of the advisory lock taken by replicas running
+// update jobs.
+const updateLockKey = 7245
+
+// Locker is core.Locker built on a postgres session advisory lock. The lock
+// is held by a connection taken from the pool, so it is released by postgres
+// if the replica dies. The connection is named after the replica, so other
+// replicas can tell who holds the lock.
+type Locker struct {
+	log     *slog.Logger
+	conn    *sqlx.DB
+	replica string
+}
+
+func NewLocker(log *slog.Logger, db *DB, replica string) *Locker {
+	return &Locker{
+		log:     log,
+		conn:    db.conn,
+		replica: replica,
+	}
+}
+
+func (l *Locker) TryLock(ctx context.Context) (func(), bool, error) {
+	conn, err := l.conn.Connx(ctx)
+	if err != nil {
+		l.log.Error("Failed to get connection for lock", "error", err)
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRowxContext(ctx, "SELECT pg_try_advisory_lock($1)", updateLockKey).Scan(&ok); err != nil {
+		l.log.Error("Failed to take lock", "error", err)
+		l.close(conn)
+		return nil, false, err
+	}
+	if !ok {
+		l.close(conn)
+		return nil, false, nil
+	}
+	// query_start of the connection keeps the time the lock has been taken,
+	// the connection is idle until the lock is released
+	_, err = conn.ExecContext(ctx, "SELECT set_config('application_name', $1, false)", l.replica)
+	if err != nil {
+		l.log.Error("Failed to name connection holding lock", "error", err)
+		l.discard(conn)
+		return nil, false, err
+	}
+	l.log.Info("Lock has been taken", "replica", l.replica)
+
+	return func() {
+		ctx := context.Background()
+		var unlocked bool
+		err := conn.QueryRowxContext(ctx, "SELECT pg_advisory_unlock($1)", updateLockKey).Scan(&unlocked)
+		if err != nil || !unlocked {
+			l.log.Error("Failed to release lock, dropping connection", "error", err)
+			l.discard(conn)
+			return
+		}
+		if _, err := conn.ExecContext(ctx, "RESET application_name"); err != nil {
+			l.discard(conn)
+			return
+		}
+		l.close(conn)
+		l.log.Info("Lock has been released", "replica", l.replica)
+	}, true, nil
+}
+
+func (l *Locker) Holder(ctx context.Context) (core.LockHolder, bool, error) {
+	var holder struct {
+		Replica string       `db:"application_name"`
+		Since   sql.NullTime `db:"query_start"`
+	}
+	err := l.conn.GetContext(ctx, &holder, `
+		SELECT a.application_name, a.query_start
+		FROM pg_locks l
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.classid = 0 AND l.objid = $1 AND l.objsubid = 1 AND l.granted
+	`, updateLockKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.LockHolder{}, false, nil
+	}
+	if err != nil {
+		l.log.Error("Failed to get lock holder", "error", err)
+		return core.LockHolder{}, false, err
+	}
+	return core.LockHolder{Replica: holder.Replica, Since: holder.Since.Time}, true, nil
+}
+
+func (l *Locker) close(conn *sqlx.Conn) {
+	if err := conn.Close(); err != nil {
+		l.log.Error("Failed to return connection of lock", "error", err)
+	}
+}
+
+// discard closes the connection instead of returning it to the pool, so a
+// lock it may still hold is released by postgres. database/sql closes the
+// connection whose Raw returns driver.ErrBadConn.
+func (l *Locker) discard(conn *sqlx.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
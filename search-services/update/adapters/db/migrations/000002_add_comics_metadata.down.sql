@@ -0,0 +1,6 @@
+ALTER TABLE comics
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS safe_title,
+    DROP COLUMN IF EXISTS alt,
+    DROP COLUMN IF EXISTS transcript,
+    DROP COLUMN IF EXISTS published;
\ No newline at end of file
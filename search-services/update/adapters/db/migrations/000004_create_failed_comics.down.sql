@@ -0,0 +1 @@
+DROP TABLE IF EXISTS failed_comics;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE failed_comics (
+    id INTEGER PRIMARY KEY,
+    stage TEXT NOT NULL,
+    error_class TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    failed_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
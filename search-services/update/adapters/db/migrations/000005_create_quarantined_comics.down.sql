@@ -0,0 +1 @@
+DROP TABLE IF EXISTS quarantined_comics;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE quarantined_comics (
+    id INTEGER PRIMARY KEY,
+    payload TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    quarantined_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS comics_raw;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE TABLE comics_raw (
+    id INTEGER PRIMARY KEY REFERENCES comics (id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
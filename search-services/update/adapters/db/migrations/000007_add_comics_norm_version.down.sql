@@ -0,0 +1 @@
+ALTER TABLE comics DROP COLUMN IF EXISTS norm_version;
\ No newline at end of file
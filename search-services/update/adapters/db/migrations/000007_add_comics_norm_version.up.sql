@@ -0,0 +1 @@
+ALTER TABLE comics ADD COLUMN norm_version INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE comics DROP COLUMN IF EXISTS hash;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE comics ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+UPDATE comics SET hash = encode(sha256(convert_to(concat_ws(chr(31),
+    url, title, safe_title, alt, transcript, coalesce(to_char(published, 'YYYY-MM-DD'), '')), 'UTF8')), 'hex');
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS comics_trash;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE comics_trash (
+    id INTEGER PRIMARY KEY,
+    url TEXT NOT NULL,
+    words TEXT[],
+    title TEXT NOT NULL DEFAULT '',
+    safe_title TEXT NOT NULL DEFAULT '',
+    alt TEXT NOT NULL DEFAULT '',
+    transcript TEXT NOT NULL DEFAULT '',
+    published DATE,
+    norm_version INTEGER NOT NULL DEFAULT 0,
+    hash TEXT NOT NULL DEFAULT '',
+    raw JSONB,
+    raw_fetched_at TIMESTAMPTZ,
+    dropped_at TIMESTAMPTZ NOT NULL
+);
\ No newline at end of file
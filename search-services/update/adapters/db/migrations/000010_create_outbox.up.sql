@@ -0,0 +1,14 @@
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    job_id BIGINT NOT NULL DEFAULT 0,
+    added INTEGER[] NOT NULL DEFAULT '{}',
+    updated INTEGER[] NOT NULL DEFAULT '{}',
+    deleted INTEGER[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE sent_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE update_jobs DROP COLUMN IF EXISTS fetch_ms;
+ALTER TABLE update_jobs DROP COLUMN IF EXISTS normalize_ms;
+ALTER TABLE update_jobs DROP COLUMN IF EXISTS store_ms;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE update_jobs ADD COLUMN fetch_ms BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE update_jobs ADD COLUMN normalize_ms BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE update_jobs ADD COLUMN store_ms BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE update_jobs DROP COLUMN IF EXISTS kind;
\ No newline at end of file
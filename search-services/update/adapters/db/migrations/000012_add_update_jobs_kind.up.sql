@@ -0,0 +1 @@
+ALTER TABLE update_jobs ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'update';
\ No newline at end of file
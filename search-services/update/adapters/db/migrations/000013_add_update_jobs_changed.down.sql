@@ -0,0 +1 @@
+ALTER TABLE update_jobs DROP COLUMN IF EXISTS changed;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE update_jobs ADD COLUMN IF NOT EXISTS changed INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
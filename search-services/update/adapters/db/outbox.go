@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jmoiron/sqlx"
+	"yadro.com/course/update/core"
+)
+
+type outboxRow struct {
+	ID        int64      `db:"id"`
+	Type      string     `db:"type"`
+	JobID     int64      `db:"job_id"`
+	Added     int64Array `db:"added"`
+	Updated   int64Array `db:"updated"`
+	Deleted   int64Array `db:"deleted"`
+	CreatedAt time.Time  `db:"created_at"`
+	Attempts  int        `db:"attempts"`
+}
+
+// addOutboxEvent saves the event into the outbox within the transaction
+// that has changed comics, so the event is published if and only if the
+// change is committed.
+func addOutboxEvent(ctx context.Context, tx *sqlx.Tx, event core.DBEvent) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (type, job_id, added, updated, deleted)
+		VALUES ($1, $2, $3, $4, $5)
+	`, event.Type, event.JobID, int64s(event.Added), int64s(event.Updated), int64s(event.Deleted))
+	return err
+}
+
+// ClaimEvents locks at most limit pending events and passes them to send in
+// the order they have been saved. Locked events are skipped by other
+// replicas, so every event is sent by one of them. Sent events are marked as
+// sent within the same transaction; the first event that fails to be sent
+// counts a failed attempt and the rest stay pending. The number of sent events
+// is returned together with the error of send.
+func (db *DB) ClaimEvents(ctx context.Context, limit int, send func(core.OutboxEvent) error) (int, error) {
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		db.log.Error("Failed to begin transaction for pending events", "error", err)
+		return 0, transient(err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("Failed to rollback transaction", "error", err)
+		}
+	}()
+
+	var rows []outboxRow
+	err = tx.SelectContext(ctx, &rows, `
+		SELECT id, type, job_id, added, updated, deleted, created_at, attempts
+		FROM outbox
+		WHERE sent_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		db.log.Error("Failed to claim pending events", "error", err)
+		return 0, transient(err)
+	}
+
+	sent := 0
+	var sendErr error
+	for _, row := range rows {
+		event := core.OutboxEvent{
+			ID: row.ID,
+			Event: core.DBEvent{
+				Type:    core.DBEventType(row.Type),
+				JobID:   row.JobID,
+				Added:   ints(row.Added),
+				Updated: ints(row.Updated),
+				Deleted: ints(row.Deleted),
+				Time:    row.CreatedAt,
+			},
+			Attempts: row.Attempts,
+		}
+		if sendErr = send(event); sendErr != nil {
+			_, err := tx.ExecContext(ctx,
+				"UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1", row.ID, sendErr.Error())
+			if err != nil {
+				db.log.Error("Failed to record failure of event "+strconv.FormatInt(row.ID, 10), "error", err)
+				return 0, transient(err)
+			}
+			break
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox SET sent_at = now() WHERE id = $1", row.ID); err != nil {
+			db.log.Error("Failed to mark event "+strconv.FormatInt(row.ID, 10)+" as sent", "error", err)
+			return 0, transient(err)
+		}
+		sent++
+	}
+	if err := tx.Commit(); err != nil {
+		db.log.Error("Failed to commit sent events", "error", err)
+		return 0, transient(err)
+	}
+	return sent, sendErr
+}
+
+// PurgeOutbox deletes events sent before the given time.
+func (db *DB) PurgeOutbox(ctx context.Context, before time.Time) error {
+	res, err := db.conn.ExecContext(ctx, "DELETE FROM outbox WHERE sent_at < $1", before)
+	if err != nil {
+		db.log.Error("Failed to purge outbox", "error", err)
+		return transient(err)
+	}
+	if purged, err := res.RowsAffected(); err == nil && purged > 0 {
+		db.log.Info(strconv.FormatInt(purged, 10) + " sent events have been purged from outbox")
+	}
+	return nil
+}
+
+// int64Array scans bigint[] columns with the array support of pgx.
+type int64Array []int64
+
+func (a *int64Array) Scan(src any) error {
+	// pgtype.Map caches scan plans and is not safe for concurrent use
+	return pgtype.NewMap().SQLScanner((*[]int64)(a)).Scan(src)
+}
+
+// int64s converts ids for array parameters, nil ids become an empty array.
+func int64s(ids []int) []int64 {
+	result := make([]int64, len(ids))
+	for i, id := range ids {
+		result[i] = int64(id)
+	}
+	return result
+}
+
+func ints(ids int64Array) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = int(id)
+	}
+	return result
+}
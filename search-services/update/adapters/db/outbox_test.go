@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInt64Array_Scan scans arrays as the pgx driver returns them to
+// database/sql, in text format.
+func TestInt64Array_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want []int
+	}{
+		{name: "ids", src: "{3,1,2}", want: []int{3, 1, 2}},
+		{name: "empty", src: "{}"},
+		{name: "null", src: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids int64Array
+			require.NoError(t, ids.Scan(tt.src))
+			assert.Equal(t, tt.want, ints(ids))
+		})
+	}
+
+	var ids int64Array
+	assert.Error(t, ids.Scan("{one}"))
+}
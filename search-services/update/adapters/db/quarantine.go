@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"yadro.com/course/update/core"
+)
+
+type quarantinedRow struct {
+	ID            int       `db:"id"`
+	Payload       string    `db:"payload"`
+	Reason        string    `db:"reason"`
+	QuarantinedAt time.Time `db:"quarantined_at"`
+}
+
+// Quarantine keeps the latest invalid payload of the comics.
+func (db *DB) Quarantine(ctx context.Context, quarantined core.QuarantinedComics) error {
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO quarantined_comics (id, payload, reason, quarantined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			reason = EXCLUDED.reason,
+			quarantined_at = EXCLUDED.quarantined_at
+	`, quarantined.ID, quarantined.Payload, quarantined.Reason, quarantined.QuarantinedAt)
+	if err != nil {
+		db.log.Error("Failed to quarantine "+strconv.Itoa(quarantined.ID)+" comics", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) ListQuarantined(ctx context.Context) ([]core.QuarantinedComics, error) {
+	var rows []quarantinedRow
+	err := db.conn.SelectContext(ctx, &rows, `
+		SELECT id, payload, reason, quarantined_at
+		FROM quarantined_comics
+		ORDER BY id
+	`)
+	if err != nil {
+		db.log.Error("Failed to list quarantined comics", "error", err)
+		return nil, err
+	}
+	quarantined := make([]core.QuarantinedComics, len(rows))
+	for i, row := range rows {
+		quarantined[i] = core.QuarantinedComics{
+			ID:            row.ID,
+			Payload:       row.Payload,
+			Reason:        row.Reason,
+			QuarantinedAt: row.QuarantinedAt,
+		}
+	}
+	return quarantined, nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"yadro.com/course/update/core"
+)
+
+type rawRow struct {
+	ID      int    `db:"id"`
+	Payload []byte `db:"payload"`
+}
+
+// ListRaw returns archived xkcd json of every stored comics.
+func (db *DB) ListRaw(ctx context.Context) ([]core.RawComics, error) {
+	var rows []rawRow
+	err := db.conn.SelectContext(ctx, &rows, "SELECT id, payload FROM comics_raw ORDER BY id")
+	if err != nil {
+		db.log.Error("Failed to list archived comics", "error", err)
+		return nil, err
+	}
+	raws := make([]core.RawComics, len(rows))
+	for i, row := range rows {
+		raws[i] = core.RawComics{ID: row.ID, Payload: row.Payload}
+	}
+	return raws, nil
+}
+
+// GetRaw returns archived xkcd json of the comics.
+func (db *DB) GetRaw(ctx context.Context, id int) ([]byte, error) {
+	var payload []byte
+	err := db.conn.GetContext(ctx, &payload, "SELECT payload FROM comics_raw WHERE id = $1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, core.ErrNotFound
+	}
+	if err != nil {
+		db.log.Error("Failed to get archived json of "+strconv.Itoa(id)+" comics", "error", err)
+		return nil, transient(err)
+	}
+	return payload, nil
+}
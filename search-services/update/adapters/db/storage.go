@@ -2,9 +2,18 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"yadro.com/course/update/core"
@@ -29,21 +38,175 @@ func New(log *slog.Logger, address string) (*DB, error) {
 	}, nil
 }
 
-func (db *DB) Add(ctx context.Context, comics core.Comics) error {
-	query := `
-		INSERT INTO comics (id, url, words)
-		VALUES ($1, $2, $3)
+// Close closes connections to db.
+func (db *DB) Close() error {
+	return db.conn.Close()
+}
+
+// batchRows bounds rows of one insert statement, so that its parameters stay
+// within the postgres limit of 65535.
+const batchRows = 1000
+
+// AddBatch upserts comics together with their archived json in one
+// transaction using multi-row inserts. Stored comics are removed from the
+// failed comics ledger and from quarantine. The event is saved into the
+// outbox within the same transaction.
+func (db *DB) AddBatch(ctx context.Context, batch []core.Comics, event core.DBEvent) error {
+	batch = dedup(batch)
+	if len(batch) == 0 {
+		return nil
+	}
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		db.log.Error("Failed to begin transaction for batch of comics", "error", err)
+		return transient(err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("Failed to rollback transaction", "error", err)
+		}
+	}()
+
+	for start := 0; start < len(batch); start += batchRows {
+		chunk := batch[start:min(start+batchRows, len(batch))]
+		if err := addComics(ctx, tx, chunk); err != nil {
+			db.log.Error("Failed to add batch of comics to db", "error", err)
+			return transient(err)
+		}
+		if err := archiveComics(ctx, tx, chunk); err != nil {
+			db.log.Error("Failed to archive json of batch of comics", "error", err)
+			return transient(err)
+		}
+	}
+
+	ids := make([]int64, len(batch))
+	for i, comics := range batch {
+		ids[i] = int64(comics.ID)
+	}
+	// stored comics are not failed anymore
+	if _, err := tx.ExecContext(ctx, "DELETE FROM failed_comics WHERE id = ANY($1)", ids); err != nil {
+		db.log.Error("Failed to clear failures of batch of comics", "error", err)
+		return transient(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quarantined_comics WHERE id = ANY($1)", ids); err != nil {
+		db.log.Error("Failed to release batch of comics from quarantine", "error", err)
+		return transient(err)
+	}
+	if err := addOutboxEvent(ctx, tx, event); err != nil {
+		db.log.Error("Failed to save event of batch of comics into outbox", "error", err)
+		return transient(err)
+	}
+	if err := tx.Commit(); err != nil {
+		db.log.Error("Failed to commit batch of comics to db", "error", err)
+		return transient(err)
+	}
+	db.log.Info(strconv.Itoa(len(batch)) + " comics have been added to db")
+	return nil
+}
+
+func addComics(ctx context.Context, tx *sqlx.Tx, batch []core.Comics) error {
+	const columns = 10
+	args := make([]any, 0, len(batch)*columns)
+	for _, comics := range batch {
+		published := sql.NullTime{Time: comics.Published, Valid: !comics.Published.IsZero()}
+		args = append(args, comics.ID, comics.URL, comics.Title, comics.SafeTitle,
+			comics.Description, comics.Transcript, published, comics.Words, comics.NormVersion, comics.Hash)
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO comics (id, url, title, safe_title, alt, transcript, published, words, norm_version, hash)
+		VALUES `+placeholders(len(batch), columns)+`
 		ON CONFLICT (id) DO UPDATE SET
 			url = EXCLUDED.url,
-			words = EXCLUDED.words
-	`
-	_, err := db.conn.Exec(query, comics.ID, comics.URL, comics.Words)
-	if err != nil {
-		db.log.Error("Failed to add "+strconv.Itoa(comics.ID)+" comics to db", "error", err)
+			title = EXCLUDED.title,
+			safe_title = EXCLUDED.safe_title,
+			alt = EXCLUDED.alt,
+			transcript = EXCLUDED.transcript,
+			published = EXCLUDED.published,
+			words = EXCLUDED.words,
+			norm_version = EXCLUDED.norm_version,
+			hash = EXCLUDED.hash
+	`, args...)
+	return err
+}
+
+func archiveComics(ctx context.Context, tx *sqlx.Tx, batch []core.Comics) error {
+	const columns = 3
+	args := make([]any, 0, len(batch)*columns)
+	now := time.Now()
+	for _, comics := range batch {
+		if len(comics.Raw) > 0 {
+			args = append(args, comics.ID, string(comics.Raw), now)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO comics_raw (id, payload, fetched_at)
+		VALUES `+placeholders(len(args)/columns, columns)+`
+		ON CONFLICT (id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			fetched_at = EXCLUDED.fetched_at
+	`, args...)
+	return err
+}
+
+// placeholders builds "($1, $2), ($3, $4)" for the given number of rows and
+// columns.
+func placeholders(rows, columns int) string {
+	var b strings.Builder
+	for row := range rows {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for column := range columns {
+			if column > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(row*columns + column + 1))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// dedup keeps the last of comics with the same id, postgres cannot upsert a
+// row twice in one statement.
+func dedup(batch []core.Comics) []core.Comics {
+	last := make(map[int]int, len(batch))
+	for i, comics := range batch {
+		last[comics.ID] = i
+	}
+	if len(last) == len(batch) {
+		return batch
+	}
+	unique := make([]core.Comics, 0, len(last))
+	for i, comics := range batch {
+		if last[comics.ID] == i {
+			unique = append(unique, comics)
+		}
+	}
+	return unique
+}
+
+// transient marks connection problems, timeouts, serialization failures and
+// deadlocks as core.ErrTransient, so the caller may retry them.
+func transient(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "40001" || pgErr.Code == "40P01" ||
+			pgErr.Code == "57P03" {
+			return fmt.Errorf("%w: %w", core.ErrTransient, err)
+		}
 		return err
 	}
-	db.log.Info("Comics " + strconv.Itoa(comics.ID) + " has been added to db")
-	return nil
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || pgconn.Timeout(err) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", core.ErrTransient, err)
+	}
+	return err
 }
 
 func (db *DB) Stats(ctx context.Context) (core.DBStats, error) {
@@ -56,6 +219,12 @@ func (db *DB) Stats(ctx context.Context) (core.DBStats, error) {
 		return core.DBStats{}, err
 	}
 
+	err = db.conn.GetContext(ctx, &stats.ComicsFailed, "SELECT COUNT(*) FROM failed_comics")
+	if err != nil {
+		db.log.Error("Failed to get amount of failed comics in db", "error", err)
+		return core.DBStats{}, err
+	}
+
 	err = db.conn.GetContext(ctx, &stats.WordsTotal, `
 		SELECT COUNT(*)
 		FROM (
@@ -95,11 +264,92 @@ func (db *DB) IDs(ctx context.Context) ([]int, error) {
 	return ids, nil
 }
 
-func (db *DB) Drop(ctx context.Context) error {
-	if _, err := db.conn.Exec("DELETE FROM comics"); err != nil {
-		db.log.Error("Failed to delete information from comics in db", "error", err)
-		return err
+// OutdatedIDs returns ids of comics normalized by another version of the
+// words analyzer.
+func (db *DB) OutdatedIDs(ctx context.Context, version int) ([]int, error) {
+	var ids []int
+	err := db.conn.SelectContext(ctx, &ids, "SELECT id FROM comics WHERE norm_version <> $1 ORDER BY id", version)
+	if err != nil {
+		db.log.Error("Failed to get ids of outdated comics from db", "error", err)
+		return nil, err
 	}
-	db.log.Info("All information about comics have been deleted in db")
-	return nil
+	return ids, nil
+}
+
+// Hashes returns content hashes of stored comics matching the filter by their
+// ids.
+func (db *DB) Hashes(ctx context.Context, filter core.RefreshFilter) (map[int]string, error) {
+	query := "SELECT id, hash FROM comics"
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Days > 0 {
+		args = append(args, filter.Days)
+		conditions = append(conditions, "published >= CURRENT_DATE - $"+strconv.Itoa(len(args))+"::int")
+	}
+	if filter.EmptyTranscript {
+		conditions = append(conditions, "transcript = ''")
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+
+	var rows []struct {
+		ID   int    `db:"id"`
+		Hash string `db:"hash"`
+	}
+	if err := db.conn.SelectContext(ctx, &rows, query, args...); err != nil {
+		db.log.Error("Failed to get hashes of comics from db", "error", err)
+		return nil, err
+	}
+	hashes := make(map[int]string, len(rows))
+	for _, row := range rows {
+		hashes[row.ID] = row.Hash
+	}
+	return hashes, nil
+}
+
+// Delete removes comics from..to with their archived json, failures and
+// quarantine records in one transaction and returns ids of deleted comics.
+// Subscribers are notified through the outbox.
+func (db *DB) Delete(ctx context.Context, from, to int) ([]int, error) {
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		db.log.Error("Failed to begin transaction for deleting comics", "error", err)
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("Failed to rollback transaction", "error", err)
+		}
+	}()
+
+	var deleted []int
+	err = tx.SelectContext(ctx, &deleted, "DELETE FROM comics WHERE id BETWEEN $1 AND $2 RETURNING id", from, to)
+	if err != nil {
+		db.log.Error("Failed to delete comics in db", "error", err)
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM failed_comics WHERE id BETWEEN $1 AND $2", from, to); err != nil {
+		db.log.Error("Failed to delete failed comics in db", "error", err)
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quarantined_comics WHERE id BETWEEN $1 AND $2", from, to); err != nil {
+		db.log.Error("Failed to delete quarantined comics in db", "error", err)
+		return nil, err
+	}
+	slices.Sort(deleted)
+	if len(deleted) > 0 {
+		if err := addOutboxEvent(ctx, tx, core.DBEvent{Type: core.DBEventDeleted, Deleted: deleted}); err != nil {
+			db.log.Error("Failed to save event of deleted comics into outbox", "error", err)
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		db.log.Error("Failed to commit deleting of comics", "error", err)
+		return nil, err
+	}
+	db.log.Info(strconv.Itoa(len(deleted)) + " comics have been deleted in db")
+	return deleted, nil
 }
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"yadro.com/course/update/core"
+)
+
+const (
+	benchComics = 3000
+	// benchWorkers matches store workers of config.yaml.
+	benchWorkers = 2
+)
+
+// BenchmarkImport compares storing a full xkcd import one comics at a time,
+// as the service does with BATCH_SIZE=1, with storing it in batches. Every
+// case stores through AddBatch with the same number of workers. It needs a
+// disposable database, every iteration deletes all comics:
+// TEST_DB_ADDRESS=postgres://... go test -run '^$' -bench Import
+// ./update/adapters/db
+func BenchmarkImport(b *testing.B) {
+	address := os.Getenv("TEST_DB_ADDRESS")
+	if address == "" {
+		b.Skip("TEST_DB_ADDRESS is not set")
+	}
+	db, err := New(slog.New(slog.DiscardHandler), address)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := db.Migrate(); err != nil {
+		b.Fatal(err)
+	}
+
+	comics := make([]core.Comics, benchComics)
+	for i := range comics {
+		id := i + 1
+		words := make([]string, 40)
+		for j := range words {
+			words[j] = "word" + strconv.Itoa((id*7+j)%500)
+		}
+		comics[i] = core.Comics{
+			ID:          id,
+			URL:         "https://imgs.xkcd.com/comics/" + strconv.Itoa(id) + ".png",
+			Title:       "Comics " + strconv.Itoa(id),
+			SafeTitle:   "Comics " + strconv.Itoa(id),
+			Description: "Alt text of comics " + strconv.Itoa(id),
+			Transcript:  "Transcript of comics " + strconv.Itoa(id),
+			Published:   time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, id),
+			Words:       words,
+			NormVersion: 1,
+			Raw:         []byte(`{"num": ` + strconv.Itoa(id) + `, "title": "Comics ` + strconv.Itoa(id) + `"}`),
+		}
+	}
+
+	for _, bench := range []struct {
+		name string
+		size int
+	}{
+		{name: "one_by_one", size: 1},
+		{name: "batch_100", size: 100},
+		{name: "batch_1000", size: 1000},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			ctx := context.Background()
+			for range b.N {
+				b.StopTimer()
+				if _, err := db.Drop(ctx); err != nil {
+					b.Fatal(err)
+				}
+				if err := db.PurgeTrash(ctx, time.Now()); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				batches := make(chan []core.Comics)
+				var wg sync.WaitGroup
+				for range benchWorkers {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for batch := range batches {
+							if err := db.AddBatch(ctx, batch, core.DBEvent{Type: core.DBEventStored}); err != nil {
+								b.Error(err)
+							}
+						}
+					}()
+				}
+				for start := 0; start < len(comics); start += bench.size {
+					batches <- comics[start:min(start+bench.size, len(comics))]
+				}
+				close(batches)
+				wg.Wait()
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(comics)), "ns/comics")
+		})
+	}
+}
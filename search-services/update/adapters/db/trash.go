@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"yadro.com/course/update/core"
+)
+
+const trashColumns = `id, url, words, title, safe_title, alt, transcript, published, norm_version, hash`
+
+// Drop moves every comics with its archived json into the trash and clears
+// failed and quarantined comics. Comics already in the trash are replaced by
+// the dropped ones. Subscribers are notified through the outbox. It returns
+// the number of dropped comics.
+func (db *DB) Drop(ctx context.Context) (int, error) {
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		db.log.Error("Failed to begin transaction for dropping comics", "error", err)
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("Failed to rollback transaction", "error", err)
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO comics_trash (`+trashColumns+`, raw, raw_fetched_at, dropped_at)
+		SELECT c.id, c.url, c.words, c.title, c.safe_title, c.alt, c.transcript, c.published,
+			c.norm_version, c.hash, r.payload, r.fetched_at, $1
+		FROM comics c
+		LEFT JOIN comics_raw r ON r.id = c.id
+		ON CONFLICT (id) DO UPDATE SET
+			url = EXCLUDED.url,
+			words = EXCLUDED.words,
+			title = EXCLUDED.title,
+			safe_title = EXCLUDED.safe_title,
+			alt = EXCLUDED.alt,
+			transcript = EXCLUDED.transcript,
+			published = EXCLUDED.published,
+			norm_version = EXCLUDED.norm_version,
+			hash = EXCLUDED.hash,
+			raw = EXCLUDED.raw,
+			raw_fetched_at = EXCLUDED.raw_fetched_at,
+			dropped_at = EXCLUDED.dropped_at
+	`, time.Now())
+	if err != nil {
+		db.log.Error("Failed to move comics into trash", "error", err)
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM comics")
+	if err != nil {
+		db.log.Error("Failed to delete information from comics in db", "error", err)
+		return 0, err
+	}
+	dropped, err := res.RowsAffected()
+	if err != nil {
+		db.log.Error("Failed to get amount of dropped comics", "error", err)
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM failed_comics"); err != nil {
+		db.log.Error("Failed to delete failed comics in db", "error", err)
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quarantined_comics"); err != nil {
+		db.log.Error("Failed to delete quarantined comics in db", "error", err)
+		return 0, err
+	}
+	if dropped > 0 {
+		if err := addOutboxEvent(ctx, tx, core.DBEvent{Type: core.DBEventDropped}); err != nil {
+			db.log.Error("Failed to save event of dropped comics into outbox", "error", err)
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		db.log.Error("Failed to commit dropping of comics", "error", err)
+		return 0, err
+	}
+	db.log.Info(strconv.FormatInt(dropped, 10) + " comics have been moved into trash")
+	return int(dropped), nil
+}
+
+// Restore moves comics of the last drop back from the trash, if they have
+// been dropped since the given time, and empties it. Comics stored after they
+// have been dropped are kept. Subscribers are notified through the outbox. It
+// returns the number of restored comics.
+func (db *DB) Restore(ctx context.Context, since time.Time) (int, error) {
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		db.log.Error("Failed to begin transaction for restoring comics", "error", err)
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("Failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// every comics of one drop has the same dropped_at
+	var last sql.NullTime
+	if err := tx.GetContext(ctx, &last, "SELECT max(dropped_at) FROM comics_trash"); err != nil {
+		db.log.Error("Failed to find last drop in trash", "error", err)
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM comics_trash
+		WHERE dropped_at < $1 OR dropped_at <> $2 OR id IN (SELECT id FROM comics)
+	`, since, last)
+	if err != nil {
+		db.log.Error("Failed to skip expired comics in trash", "error", err)
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO comics (`+trashColumns+`)
+		SELECT `+trashColumns+` FROM comics_trash
+	`)
+	if err != nil {
+		db.log.Error("Failed to restore comics from trash", "error", err)
+		return 0, err
+	}
+	restored, err := res.RowsAffected()
+	if err != nil {
+		db.log.Error("Failed to get amount of restored comics", "error", err)
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO comics_raw (id, payload, fetched_at)
+		SELECT id, raw, raw_fetched_at FROM comics_trash WHERE raw IS NOT NULL
+	`)
+	if err != nil {
+		db.log.Error("Failed to restore archived json of comics from trash", "error", err)
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM comics_trash"); err != nil {
+		db.log.Error("Failed to empty trash", "error", err)
+		return 0, err
+	}
+	if restored > 0 {
+		if err := addOutboxEvent(ctx, tx, core.DBEvent{Type: core.DBEventRestored}); err != nil {
+			db.log.Error("Failed to save event of restored comics into outbox", "error", err)
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		db.log.Error("Failed to commit restoring of comics", "error", err)
+		return 0, err
+	}
+	db.log.Info(strconv.FormatInt(restored, 10) + " comics have been restored from trash")
+	return int(restored), nil
+}
+
+// PurgeTrash deletes comics dropped before the given time for good.
+func (db *DB) PurgeTrash(ctx context.Context, before time.Time) error {
+	res, err := db.conn.ExecContext(ctx, "DELETE FROM comics_trash WHERE dropped_at < $1", before)
+	if err != nil {
+		db.log.Error("Failed to purge trash", "error", err)
+		return err
+	}
+	if purged, err := res.RowsAffected(); err == nil && purged > 0 {
+		db.log.Info(strconv.FormatInt(purged, 10) + " expired comics have been purged from trash")
+	}
+	return nil
+}
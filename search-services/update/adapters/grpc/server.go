@@ -2,8 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	updatepb "yadro.com/course/proto/update"
 	"yadro.com/course/update/core"
 )
@@ -21,25 +27,234 @@ func (s *Server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, erro
 	return nil, nil
 }
 
+const watchInterval = 500 * time.Millisecond
+
 func (s *Server) Status(ctx context.Context, _ *emptypb.Empty) (*updatepb.StatusReply, error) {
-	status := s.service.Status(ctx)
-	var result updatepb.Status
+	state := s.service.Status(ctx)
+	return &updatepb.StatusReply{
+		Status:    toStatus(state.Status),
+		LastRun:   toTimestamp(state.LastRun),
+		NextRun:   toTimestamp(state.NextRun),
+		Holder:    state.Holder.Replica,
+		HeldSince: toTimestamp(state.Holder.Since),
+	}, nil
+}
+
+func (s *Server) WatchUpdate(_ *emptypb.Empty, stream updatepb.Update_WatchUpdateServer) error {
+	for progress := range s.service.WatchProgress(stream.Context(), watchInterval) {
+		err := stream.Send(&updatepb.UpdateProgress{
+			Status:     toStatus(progress.Status),
+			Total:      int64(progress.Total),
+			Fetched:    int64(progress.Fetched),
+			Normalized: int64(progress.Normalized),
+			Stored:     int64(progress.Stored),
+			Failed:     int64(progress.Failed),
+			Changed:    int64(progress.Changed),
+			CurrentId:  int64(progress.CurrentID),
+			Timings:    toTimings(progress.Timings),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toStatus(status core.ServiceStatus) updatepb.Status {
 	switch status {
 	case core.StatusIdle:
-		result = updatepb.Status_STATUS_IDLE
+		return updatepb.Status_STATUS_IDLE
 	case core.StatusRunning:
-		result = updatepb.Status_STATUS_RUNNING
+		return updatepb.Status_STATUS_RUNNING
 	default:
-		result = updatepb.Status_STATUS_UNSPECIFIED
+		return updatepb.Status_STATUS_UNSPECIFIED
+	}
+}
+
+func toTimings(timings core.StageTimings) *updatepb.StageTimings {
+	return &updatepb.StageTimings{
+		Fetch:     durationpb.New(timings.Fetch),
+		Normalize: durationpb.New(timings.Normalize),
+		Store:     durationpb.New(timings.Store),
+	}
+}
+
+func toJob(job core.Job) *updatepb.Job {
+	var outcome updatepb.JobOutcome
+	switch job.Outcome {
+	case core.JobRunning:
+		outcome = updatepb.JobOutcome_JOB_OUTCOME_RUNNING
+	case core.JobSucceeded:
+		outcome = updatepb.JobOutcome_JOB_OUTCOME_SUCCEEDED
+	case core.JobFailed:
+		outcome = updatepb.JobOutcome_JOB_OUTCOME_FAILED
+	case core.JobCanceled:
+		outcome = updatepb.JobOutcome_JOB_OUTCOME_CANCELED
+	}
+	return &updatepb.Job{
+		Id:          job.ID,
+		Kind:        string(job.Kind),
+		StartedAt:   toTimestamp(job.StartedAt),
+		FinishedAt:  toTimestamp(job.FinishedAt),
+		Outcome:     outcome,
+		Total:       int64(job.Total),
+		Fetched:     int64(job.Fetched),
+		Normalized:  int64(job.Normalized),
+		Stored:      int64(job.Stored),
+		Failed:      int64(job.Failed),
+		Changed:     int64(job.Changed),
+		Timings:     toTimings(job.Timings),
+		Error:       job.Error,
+		TriggeredBy: job.TriggeredBy,
+	}
+}
+
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, core.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, core.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, core.ErrBadArguments):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, core.ErrJobFinished):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+// toJobStatusError converts the error of starting a job. ErrAlreadyExists
+// carries the running job in its details, so that callers can follow it.
+func toJobStatusError(job core.Job, err error) error {
+	if !errors.Is(err, core.ErrAlreadyExists) || job.ID == 0 {
+		return toStatusError(err)
+	}
+	st, detailsErr := status.New(codes.AlreadyExists, err.Error()).WithDetails(toJob(job))
+	if detailsErr != nil {
+		return toStatusError(err)
+	}
+	return st.Err()
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func (s *Server) Update(ctx context.Context, in *updatepb.UpdateRequest) (*updatepb.Job, error) {
+	job, err := s.service.Update(ctx, in.TriggeredBy)
+	if err != nil {
+		return nil, toJobStatusError(job, err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) GetJob(ctx context.Context, in *updatepb.JobRequest) (*updatepb.Job, error) {
+	job, err := s.service.GetJob(ctx, in.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) ListJobs(ctx context.Context, in *updatepb.ListJobsRequest) (*updatepb.ListJobsReply, error) {
+	jobs, err := s.service.ListJobs(ctx, int(in.Limit), int(in.Offset))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	reply := &updatepb.ListJobsReply{Jobs: make([]*updatepb.Job, len(jobs))}
+	for i, job := range jobs {
+		reply.Jobs[i] = toJob(job)
 	}
-	return &updatepb.StatusReply{Status: result}, nil
+	return reply, nil
 }
 
-func (s *Server) Update(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
-	if err := s.service.Update(ctx); err != nil {
+func (s *Server) CancelJob(ctx context.Context, in *updatepb.JobRequest) (*emptypb.Empty, error) {
+	if err := s.service.CancelJob(ctx, in.Id); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) RetryFailed(ctx context.Context, in *updatepb.UpdateRequest) (*updatepb.Job, error) {
+	job, err := s.service.RetryFailed(ctx, in.TriggeredBy)
+	if err != nil {
+		return nil, toJobStatusError(job, err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) Reprocess(ctx context.Context, in *updatepb.UpdateRequest) (*updatepb.Job, error) {
+	job, err := s.service.Reprocess(ctx, in.TriggeredBy)
+	if err != nil {
+		return nil, toJobStatusError(job, err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) Refetch(ctx context.Context, in *updatepb.RefetchRequest) (*updatepb.Job, error) {
+	job, err := s.service.Refetch(ctx, in.TriggeredBy, int(in.From), int(in.To))
+	if err != nil {
+		return nil, toJobStatusError(job, err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) Refresh(ctx context.Context, in *updatepb.RefreshRequest) (*updatepb.Job, error) {
+	job, err := s.service.Refresh(ctx, in.TriggeredBy, core.RefreshFilter{
+		Days:            int(in.Days),
+		EmptyTranscript: in.EmptyTranscript,
+	})
+	if err != nil {
+		return nil, toJobStatusError(job, err)
+	}
+	return toJob(job), nil
+}
+
+func (s *Server) Delete(ctx context.Context, in *updatepb.DeleteRequest) (*updatepb.DeleteReply, error) {
+	deleted, err := s.service.Delete(ctx, int(in.From), int(in.To))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &updatepb.DeleteReply{Deleted: int64(deleted)}, nil
+}
+
+func (s *Server) ListFailed(ctx context.Context, _ *emptypb.Empty) (*updatepb.ListFailedReply, error) {
+	failed, err := s.service.ListFailed(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return nil, nil
+	reply := &updatepb.ListFailedReply{Comics: make([]*updatepb.FailedComics, len(failed))}
+	for i, f := range failed {
+		reply.Comics[i] = &updatepb.FailedComics{
+			Id:         int64(f.ID),
+			Stage:      string(f.Stage),
+			ErrorClass: string(f.ErrorClass),
+			Error:      f.Error,
+			Attempts:   int64(f.Attempts),
+			FailedAt:   toTimestamp(f.FailedAt),
+		}
+	}
+	return reply, nil
+}
+
+func (s *Server) ListQuarantined(ctx context.Context, _ *emptypb.Empty) (*updatepb.ListQuarantinedReply, error) {
+	quarantined, err := s.service.ListQuarantined(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply := &updatepb.ListQuarantinedReply{Comics: make([]*updatepb.QuarantinedComics, len(quarantined))}
+	for i, q := range quarantined {
+		reply.Comics[i] = &updatepb.QuarantinedComics{
+			Id:            int64(q.ID),
+			Payload:       q.Payload,
+			Reason:        q.Reason,
+			QuarantinedAt: toTimestamp(q.QuarantinedAt),
+		}
+	}
+	return reply, nil
 }
 
 func (s *Server) Stats(ctx context.Context, _ *emptypb.Empty) (*updatepb.StatsReply, error) {
@@ -52,13 +267,22 @@ func (s *Server) Stats(ctx context.Context, _ *emptypb.Empty) (*updatepb.StatsRe
 		WordsUnique:   int64(serviceStats.WordsUnique),
 		ComicsTotal:   int64(serviceStats.ComicsTotal),
 		ComicsFetched: int64(serviceStats.ComicsFetched),
+		ComicsFailed:  int64(serviceStats.ComicsFailed),
 	}, nil
 }
 
 func (s *Server) Drop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
 	err := s.service.Drop(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 	return nil, nil
 }
+
+func (s *Server) Restore(ctx context.Context, _ *emptypb.Empty) (*updatepb.RestoreReply, error) {
+	restored, err := s.service.Restore(ctx)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &updatepb.RestoreReply{Restored: int64(restored)}, nil
+}
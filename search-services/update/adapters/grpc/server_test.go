@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	updatepb "yadro.com/course/proto/update"
+	"yadro.com/course/update/core"
+)
+
+// heldLocker is a lock shared by replicas that another replica holds.
+type heldLocker struct{}
+
+func (heldLocker) TryLock(context.Context) (func(), bool, error) {
+	return nil, false, nil
+}
+
+func (heldLocker) Holder(context.Context) (core.LockHolder, bool, error) {
+	return core.LockHolder{Replica: "update-2"}, true, nil
+}
+
+func TestServer_LockHeldByAnotherReplica(t *testing.T) {
+	service, err := core.NewService(slog.Default(), nil, nil, nil, nil, heldLocker{},
+		core.PipelinePolicy{Fetch: 1, Normalize: 1, Store: 1}, core.RetryPolicy{}, core.BatchPolicy{}, 0)
+	require.NoError(t, err)
+	server := NewServer(service)
+
+	_, err = server.Drop(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = server.Restore(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+// runningUpdater refuses to start jobs, because its job is still running.
+type runningUpdater struct {
+	core.Updater
+	job core.Job
+}
+
+func (u runningUpdater) Update(context.Context, string) (core.Job, error) {
+	return u.job, core.ErrAlreadyExists
+}
+
+func TestServer_UpdateReturnsRunningJob(t *testing.T) {
+	server := NewServer(runningUpdater{job: core.Job{ID: 7, Kind: core.JobUpdate, Outcome: core.JobRunning}})
+
+	_, err := server.Update(context.Background(), &updatepb.UpdateRequest{})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	job, ok := details[0].(*updatepb.Job)
+	require.True(t, ok)
+	assert.Equal(t, int64(7), job.Id)
+	assert.Equal(t, updatepb.JobOutcome_JOB_OUTCOME_RUNNING, job.Outcome)
+}
+
+func TestServer_UpdateRunningOnAnotherReplica(t *testing.T) {
+	server := NewServer(runningUpdater{})
+
+	_, err := server.Update(context.Background(), &updatepb.UpdateRequest{})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Empty(t, status.Convert(err).Details())
+}
@@ -3,25 +3,48 @@ package nats
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"yadro.com/course/events"
+	"yadro.com/course/update/core"
 )
 
+const publishTimeout = 10 * time.Second
+
+// NatsPublisher publishes events into the JetStream stream, so they are kept
+// until subscribers acknowledge them.
 type NatsPublisher struct {
 	log *slog.Logger
 	nc  *nats.Conn
+	js  jetstream.JetStream
 }
 
-func NewNatsPublisher(log *slog.Logger, brokerAddress string) (*NatsPublisher, error) {
+func NewNatsPublisher(log *slog.Logger, brokerAddress, stream string, maxAge time.Duration) (*NatsPublisher, error) {
 	nc, err := nats.Connect(brokerAddress)
 	if err != nil {
 		return &NatsPublisher{}, err
 	}
 	log.Info("connected to broker")
 
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return &NatsPublisher{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if _, err := events.EnsureStream(ctx, js, stream, maxAge); err != nil {
+		nc.Close()
+		return &NatsPublisher{}, err
+	}
+	log.Info("stream is ready", "stream", stream)
+
 	return &NatsPublisher{
 		log: log,
 		nc:  nc,
+		js:  js,
 	}, nil
 }
 
@@ -29,15 +52,42 @@ func (np *NatsPublisher) Close() {
 	np.nc.Close()
 }
 
-func (np *NatsPublisher) SendDBChangedEvent(ctx context.Context) error {
-	err := np.nc.Publish("xkcd.db.updated", []byte("XKCD DB has been updated"))
+func (np *NatsPublisher) SendDBChangedEvent(ctx context.Context, event core.DBEvent) error {
+	data, err := events.Encode(toEvent(event))
 	if err != nil {
-		np.log.Error("could not publish message", "error", err)
+		np.log.Error("could not encode message", "error", err)
 		return err
 	}
-	np.log.Info("DB changed event has been sent")
-	if err := np.nc.Flush(); err != nil {
-		np.log.Error("Error flushing nuts", "error", err)
+	// Publish waits until the stream has stored the event
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	ack, err := np.js.Publish(ctx, events.SubjectDBChanged, data)
+	if err != nil {
+		np.log.Error("could not publish message", "error", err)
+		return err
 	}
+	np.log.Info("DB changed event has been sent", "type", event.Type, "job", event.JobID,
+		"stream", ack.Stream, "sequence", ack.Sequence)
 	return nil
 }
+
+func toEvent(event core.DBEvent) events.DBChanged {
+	result := events.DBChanged{
+		JobID:   event.JobID,
+		Added:   event.Added,
+		Updated: event.Updated,
+		Deleted: event.Deleted,
+		Time:    event.Time,
+	}
+	switch event.Type {
+	case core.DBEventStored:
+		result.Type = events.TypeComicsStored
+	case core.DBEventDeleted:
+		result.Type = events.TypeComicsDeleted
+	case core.DBEventDropped:
+		result.Type = events.TypeDBDropped
+	case core.DBEventRestored:
+		result.Type = events.TypeDBRestored
+	}
+	return result
+}
@@ -2,11 +2,15 @@ package words
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	wordspb "yadro.com/course/proto/words"
+	"yadro.com/course/update/core"
 )
 
 type Client struct {
@@ -27,14 +31,33 @@ func NewClient(address string, log *slog.Logger) (*Client, error) {
 
 func (c Client) Norm(ctx context.Context, phrase string) ([]string, error) {
 	c.log.Info("Sending respone to word server")
-	words, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase})
+	// words of comics keep duplicates, search ranks comics by term frequencies
+	words, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase, KeepDuplicates: true})
 	if err != nil {
 		c.log.Error("Failed to get good response from word server", "error", err)
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+			return nil, fmt.Errorf("%w: %w", core.ErrTransient, err)
+		}
 		return nil, err
 	}
 	return words.Words, nil
 }
 
+// Version returns version of the words analyzer.
+func (c Client) Version(ctx context.Context) (int, error) {
+	reply, err := c.client.Version(ctx, nil)
+	if err != nil {
+		c.log.Error("Failed to get version of words analyzer", "error", err)
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+			return 0, fmt.Errorf("%w: %w", core.ErrTransient, err)
+		}
+		return 0, err
+	}
+	return int(reply.Version), nil
+}
+
 func (c Client) Ping(ctx context.Context) error {
 	_, err := c.client.Ping(ctx, nil)
 	return err
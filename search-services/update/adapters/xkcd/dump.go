@@ -0,0 +1,154 @@
+package xkcd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"yadro.com/course/update/core"
+)
+
+const infoFile = "info.0.json"
+
+// Dump reads comics from a local dump instead of xkcd. The dump is either a
+// directory of <id>/info.0.json files or a .tar.gz archive of such directory.
+// Directory is read on every call, so it may be extended while the service
+// runs; archive is loaded into memory once.
+type Dump struct {
+	log     *slog.Logger
+	dir     string
+	archive map[int][]byte
+}
+
+func NewDump(dumpPath string, log *slog.Logger) (*Dump, error) {
+	if dumpPath == "" {
+		return nil, fmt.Errorf("empty dump path specified")
+	}
+	if IsArchive(dumpPath) {
+		archive, err := readArchive(dumpPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dump archive %q: %w", dumpPath, err)
+		}
+		log.Info("Dump archive has been loaded", "path", dumpPath, "comics", len(archive))
+		return &Dump{log: log, archive: archive}, nil
+	}
+
+	info, err := os.Stat(dumpPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("dump %q is neither a directory nor a .tar.gz archive", dumpPath)
+	}
+	return &Dump{log: log, dir: dumpPath}, nil
+}
+
+// IsArchive tells whether the dump path points to a .tar.gz archive.
+func IsArchive(dumpPath string) bool {
+	return strings.HasSuffix(dumpPath, ".tar.gz") || strings.HasSuffix(dumpPath, ".tgz")
+}
+
+func (d *Dump) Get(ctx context.Context, id int) (core.XKCDInfo, error) {
+	var data []byte
+	if d.archive != nil {
+		var ok bool
+		if data, ok = d.archive[id]; !ok {
+			return core.XKCDInfo{}, core.ErrNotFound
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(filepath.Join(d.dir, strconv.Itoa(id), infoFile))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return core.XKCDInfo{}, core.ErrNotFound
+			}
+			d.log.Error("Failed to read comics "+strconv.Itoa(id)+" from dump", "error", err)
+			return core.XKCDInfo{}, err
+		}
+	}
+
+	info, err := decodeInfo(id, data)
+	if err != nil {
+		d.log.Error("Failed to parse json of comics id: "+strconv.Itoa(id), "error", err)
+		return core.XKCDInfo{}, err
+	}
+	return info, nil
+}
+
+// Decode builds comics info from json previously read from the dump.
+func (d *Dump) Decode(id int, payload []byte) (core.XKCDInfo, error) {
+	return decodeInfo(id, payload)
+}
+
+// LastID returns the biggest comics id found in the dump.
+func (d *Dump) LastID(ctx context.Context) (int, error) {
+	last := 0
+	if d.archive != nil {
+		for id := range d.archive {
+			last = max(last, id)
+		}
+		return last, nil
+	}
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		d.log.Error("Failed to read dump directory", "error", err)
+		return -1, err
+	}
+	for _, entry := range entries {
+		if id, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+			last = max(last, id)
+		}
+	}
+	return last, nil
+}
+
+// readArchive loads every <id>/info.0.json of the archive, leading
+// directories of the entries are ignored.
+func readArchive(archivePath string) (map[int][]byte, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
+
+	archive := make(map[int][]byte)
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return archive, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || path.Base(header.Name) != infoFile {
+			continue
+		}
+		id, err := strconv.Atoi(path.Base(path.Dir(header.Name)))
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		archive[id] = data
+	}
+}
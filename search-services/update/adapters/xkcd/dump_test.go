@@ -0,0 +1,113 @@
+package xkcd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/update/core"
+)
+
+// dumpFiles are the files of a dump, comics 2 is missing and comics 4 is
+// truncated.
+var dumpFiles = map[string]string{
+	"1/info.0.json":     `{"num": 1, "title": "Barrel - Part 1", "img": "https://imgs.xkcd.com/comics/barrel_cropped_(1).jpg"}`,
+	"3/info.0.json":     `{"num": 3, "title": "Island (sketch)"}`,
+	"4/info.0.json":     `{"num": 4, "title": "Landsc`,
+	"notes/info.0.json": `{"num": 100, "title": "Not a comics directory"}`,
+}
+
+func writeDumpDir(t *testing.T) string {
+	dir := t.TempDir()
+	for name, data := range dumpFiles {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	return dir
+}
+
+// writeDumpArchive packs the dump under a leading directory, as tar does for
+// a directory given by name.
+func writeDumpArchive(t *testing.T) string {
+	archivePath := filepath.Join(t.TempDir(), "dump.tar.gz")
+	file, err := os.Create(archivePath)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for name, data := range dumpFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     "xkcd-dump/" + name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+		}))
+		_, err := tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+	return archivePath
+}
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T) string
+	}{
+		{name: "directory", write: writeDumpDir},
+		{name: "archive", write: writeDumpArchive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dump, err := NewDump(tt.write(t), slog.New(slog.DiscardHandler))
+			require.NoError(t, err)
+
+			last, err := dump.LastID(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 4, last)
+
+			info, err := dump.Get(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, info.ID)
+			assert.Equal(t, "Barrel - Part 1", info.Title)
+			assert.Equal(t, "https://imgs.xkcd.com/comics/barrel_cropped_(1).jpg", info.URL)
+			assert.Equal(t, dumpFiles["1/info.0.json"], string(info.Raw))
+
+			_, err = dump.Get(ctx, 2)
+			assert.ErrorIs(t, err, core.ErrNotFound)
+
+			_, err = dump.Get(ctx, 4)
+			var invalid *core.InvalidPayloadError
+			require.ErrorAs(t, err, &invalid)
+			assert.Equal(t, 4, invalid.ID)
+		})
+	}
+}
+
+func TestNewDump_Errors(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	_, err := NewDump("", log)
+	assert.Error(t, err)
+
+	_, err = NewDump(filepath.Join(t.TempDir(), "missing"), log)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	file := filepath.Join(t.TempDir(), "dump.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{}`), 0o644))
+	_, err = NewDump(file, log)
+	assert.Error(t, err)
+
+	corrupt := filepath.Join(t.TempDir(), "dump.tar.gz")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not gzip"), 0o644))
+	_, err = NewDump(corrupt, log)
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -33,20 +34,41 @@ func NewClient(url string, timeout time.Duration, log *slog.Logger) (*Client, er
 }
 
 func (c Client) Get(ctx context.Context, id int) (core.XKCDInfo, error) {
+	data, err := c.Raw(ctx, id)
+	if err != nil {
+		return core.XKCDInfo{}, err
+	}
+
+	info, err := decodeInfo(id, data)
+	if err != nil {
+		c.log.Error("Failed to parse json of comics id: "+strconv.Itoa(id), "error", err)
+		return core.XKCDInfo{}, err
+	}
+
+	c.log.Info("All information about comics id: " + strconv.Itoa(id) + "have been gotten")
+
+	return info, nil
+}
+
+// Raw returns json of the comics as it is served by xkcd.
+func (c Client) Raw(ctx context.Context, id int) ([]byte, error) {
 	url := c.url + "/" + strconv.Itoa(id) + urlEnd
 
 	c.log.Info("Building request to xkcd on url: " + url)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		c.log.Error("failed to build request to xkcd", "error", err)
-		return core.XKCDInfo{}, err
+		return nil, err
 	}
 
 	c.log.Info("Send request to url: " + url)
 	resp, err := c.client.Do(req)
 	if err != nil {
 		c.log.Error("Request to url: "+url+" failed", "error", err)
-		return core.XKCDInfo{}, err
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", core.ErrTransient, err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -55,39 +77,100 @@ func (c Client) Get(ctx context.Context, id int) (core.XKCDInfo, error) {
 	}()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return core.XKCDInfo{}, core.ErrNotFound
+		return nil, core.ErrNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		c.log.Error("Got strange status code in request for getting comics id: "+strconv.Itoa(id), "error", err)
-		return core.XKCDInfo{}, errors.New("unknown status: " + strconv.Itoa(resp.StatusCode))
+		err := errors.New("unknown status: " + strconv.Itoa(resp.StatusCode))
+		if isTransientStatus(resp.StatusCode) {
+			return nil, fmt.Errorf("%w: %w", core.ErrTransient, err)
+		}
+		return nil, err
 	}
 
-	var rawData map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		c.log.Error("Failed to parse json of comics id: "+strconv.Itoa(id), "error", err)
-		return core.XKCDInfo{}, err
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.log.Error("Failed to read response for comics id: "+strconv.Itoa(id), "error", err)
+		return nil, fmt.Errorf("%w: %w", core.ErrTransient, err)
 	}
+	return data, nil
+}
 
-	info := core.XKCDInfo{
-		ID:          int(rawData["num"].(float64)),
-		URL:         rawData["img"].(string),
-		Title:       rawData["title"].(string),
-		Description: rawData["alt"].(string),
-		SafeTitle:   rawData["safe_title"].(string),
-		Transcript:  rawData["transcript"].(string),
+// infoJSON is comics json as it is served by xkcd. Absent and null fields are
+// left empty; news, link and extra_parts are accepted but not used.
+type infoJSON struct {
+	Num        int             `json:"num"`
+	Year       string          `json:"year"`
+	Month      string          `json:"month"`
+	Day        string          `json:"day"`
+	Title      string          `json:"title"`
+	SafeTitle  string          `json:"safe_title"`
+	Alt        string          `json:"alt"`
+	Transcript string          `json:"transcript"`
+	Img        string          `json:"img"`
+	Link       string          `json:"link"`
+	News       string          `json:"news"`
+	ExtraParts json.RawMessage `json:"extra_parts"`
+}
+
+// decodeInfo builds comics info from xkcd json. Payloads that cannot be
+// decoded or belong to another comics are reported as
+// core.InvalidPayloadError, id 0 accepts any comics.
+func decodeInfo(id int, data []byte) (core.XKCDInfo, error) {
+	var raw infoJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return core.XKCDInfo{}, &core.InvalidPayloadError{ID: id, Payload: data, Reason: err.Error()}
 	}
 
-	c.log.Info("All information about comics id: " + strconv.Itoa(id) + "have been gotten")
+	var reason string
+	switch {
+	case raw.Num <= 0:
+		reason = "num is absent or not positive"
+	case id != 0 && raw.Num != id:
+		reason = "num " + strconv.Itoa(raw.Num) + " does not match requested comics"
+	case raw.Title == "" && raw.SafeTitle == "":
+		reason = "title is absent"
+	}
+	if reason != "" {
+		return core.XKCDInfo{}, &core.InvalidPayloadError{ID: id, Payload: data, Reason: reason}
+	}
 
-	return info, nil
+	title := raw.Title
+	if title == "" {
+		title = raw.SafeTitle
+	}
+	safeTitle := raw.SafeTitle
+	if safeTitle == "" {
+		safeTitle = raw.Title
+	}
+	return core.XKCDInfo{
+		ID:          raw.Num,
+		URL:         raw.Img,
+		Title:       title,
+		Description: raw.Alt,
+		SafeTitle:   safeTitle,
+		Transcript:  raw.Transcript,
+		Published:   parseDate(raw.Year, raw.Month, raw.Day),
+		Raw:         data,
+	}, nil
+}
+
+// Decode builds comics info from json previously returned by Raw.
+func (c Client) Decode(id int, payload []byte) (core.XKCDInfo, error) {
+	return decodeInfo(id, payload)
 }
 
 func (c Client) LastID(ctx context.Context) (int, error) {
 	url := c.url + urlEnd
 
 	c.log.Info("Send request in purpose of getting last comics id")
-	resp, err := c.client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		c.log.Error("failed to build request to xkcd", "error", err)
+		return -1, err
+	}
+	resp, err := c.client.Do(req)
 	if err != nil {
 		c.log.Error("Failed to send request for getting last comics id", "error", err)
 		return -1, err
@@ -99,19 +182,42 @@ func (c Client) LastID(ctx context.Context) (int, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
+		err := errors.New("unknown status: " + strconv.Itoa(resp.StatusCode))
 		c.log.Error("Got strange status code in request for getting last comics id", "error", err)
 		return -1, err
 	}
 
-	var rawData map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.log.Error("Failed to read response for last comics", "error", err)
+		return -1, err
+	}
+	info, err := decodeInfo(0, data)
+	if err != nil {
 		c.log.Error("Failed to parse json of last id comics", "error", err)
 		return -1, err
 	}
-
-	id := int(rawData["num"].(float64))
+	id := info.ID
 
 	c.log.Info("Last id of comics' has been gotten: " + strconv.Itoa(id))
 
 	return id, nil
 }
+
+// isTransientStatus tells whether the request may succeed if it is repeated
+// later.
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseDate builds publish date from the year, month and day strings of xkcd
+// json. Zero time is returned if the date is absent or malformed.
+func parseDate(year, month, day string) time.Time {
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errY != nil || errM != nil || errD != nil {
+		return time.Time{}
+	}
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,143 @@
+package xkcd
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/update/core"
+)
+
+func TestDecodeInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		payload string
+		want    core.XKCDInfo
+		reason  string
+	}{
+		{
+			name:    "full",
+			id:      353,
+			payload: `{"num": 353, "year": "2007", "month": "12", "day": "5", "title": "Python", "safe_title": "Python", "alt": "I wrote 20 short programs", "transcript": "[[ Guy 1 is talking to Guy 2 ]]", "img": "https://imgs.xkcd.com/comics/python.png"}`,
+			want: core.XKCDInfo{
+				ID:          353,
+				URL:         "https://imgs.xkcd.com/comics/python.png",
+				Title:       "Python",
+				SafeTitle:   "Python",
+				Description: "I wrote 20 short programs",
+				Transcript:  "[[ Guy 1 is talking to Guy 2 ]]",
+				Published:   time.Date(2007, 12, 5, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "null transcript",
+			id:      2000,
+			payload: `{"num": 2000, "title": "xkcd Phone 2000", "transcript": null}`,
+			want:    core.XKCDInfo{ID: 2000, Title: "xkcd Phone 2000", SafeTitle: "xkcd Phone 2000"},
+		},
+		{
+			name:    "missing transcript",
+			id:      2000,
+			payload: `{"num": 2000, "safe_title": "xkcd Phone 2000"}`,
+			want:    core.XKCDInfo{ID: 2000, Title: "xkcd Phone 2000", SafeTitle: "xkcd Phone 2000"},
+		},
+		{
+			name:    "unknown fields",
+			id:      1,
+			payload: `{"num": 1, "title": "Barrel - Part 1", "extra_parts": {"pre": "<div>"}, "news": "", "link": "", "interactive": true}`,
+			want:    core.XKCDInfo{ID: 1, Title: "Barrel - Part 1", SafeTitle: "Barrel - Part 1"},
+		},
+		{
+			name:    "malformed date",
+			id:      1,
+			payload: `{"num": 1, "title": "Barrel - Part 1", "year": "2006", "month": "jan", "day": "1"}`,
+			want:    core.XKCDInfo{ID: 1, Title: "Barrel - Part 1", SafeTitle: "Barrel - Part 1"},
+		},
+		{
+			name:    "any comics for id 0",
+			id:      0,
+			payload: `{"num": 3000, "title": "Latest"}`,
+			want:    core.XKCDInfo{ID: 3000, Title: "Latest", SafeTitle: "Latest"},
+		},
+		{
+			name:    "missing num",
+			id:      5,
+			payload: `{"title": "Blown apart"}`,
+			reason:  "num is absent or not positive",
+		},
+		{
+			name:    "num of another comics",
+			id:      5,
+			payload: `{"num": 6, "title": "Irony"}`,
+			reason:  "num 6 does not match requested comics",
+		},
+		{
+			name:    "missing title",
+			id:      5,
+			payload: `{"num": 5}`,
+			reason:  "title is absent",
+		},
+		{
+			name:    "truncated",
+			id:      5,
+			payload: `{"num": 5, "title": "Blown ap`,
+			reason:  "unexpected end of JSON input",
+		},
+		{
+			name:    "num is string",
+			id:      5,
+			payload: `{"num": "5", "title": "Blown apart"}`,
+			reason:  "num of type int",
+		},
+		{
+			name:    "transcript is number",
+			id:      5,
+			payload: `{"num": 5, "title": "Blown apart", "transcript": 5}`,
+			reason:  "transcript of type string",
+		},
+		{
+			name:    "not object",
+			id:      5,
+			payload: `[5]`,
+			reason:  "cannot unmarshal array",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := decodeInfo(tt.id, []byte(tt.payload))
+			if tt.reason != "" {
+				var invalid *core.InvalidPayloadError
+				require.ErrorAs(t, err, &invalid)
+				assert.Equal(t, tt.id, invalid.ID)
+				assert.Equal(t, tt.payload, string(invalid.Payload))
+				assert.Contains(t, invalid.Reason, tt.reason)
+				return
+			}
+			require.NoError(t, err)
+			tt.want.Raw = []byte(tt.payload)
+			assert.Equal(t, tt.want, info)
+		})
+	}
+}
+
+func TestClient_Get_InvalidPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"num": 7, "title": "Pho`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), 7)
+	var invalid *core.InvalidPayloadError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, 7, invalid.ID)
+	assert.NotErrorIs(t, err, core.ErrTransient)
+}
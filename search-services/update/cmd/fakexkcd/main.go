@@ -0,0 +1,297 @@
+// Command fakexkcd serves a small xkcd compatible API from an embedded set of
+// comics, so the update service can be run end-to-end without network. Comics
+// that are not in the fixtures are generated up to -count. Latency, random
+// 5xx/429 errors of comics and of the latest comics, missing and malformed
+// comics are configurable with flags or the corresponding environment
+// variables.
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed fixtures.json
+var fixturesJSON []byte
+
+type info struct {
+	Num        int    `json:"num"`
+	Year       string `json:"year"`
+	Month      string `json:"month"`
+	Day        string `json:"day"`
+	Title      string `json:"title"`
+	SafeTitle  string `json:"safe_title"`
+	Alt        string `json:"alt"`
+	Transcript string `json:"transcript"`
+	Img        string `json:"img"`
+	Link       string `json:"link"`
+	News       string `json:"news"`
+}
+
+type config struct {
+	address       string
+	latency       time.Duration
+	latencyJitter time.Duration
+	errorRate     float64
+	lastErrorRate float64
+	missing       map[int]bool
+	malformed     map[int]bool
+	count         int
+}
+
+type server struct {
+	log      *slog.Logger
+	cfg      config
+	fixtures map[int]info
+}
+
+func main() {
+	var (
+		cfg       config
+		missing   string
+		malformed string
+	)
+	flag.StringVar(&cfg.address, "address", env("FAKE_ADDRESS", ":8080"), "address to listen on")
+	flag.DurationVar(&cfg.latency, "latency", envDuration("FAKE_LATENCY", 0), "delay of every response")
+	flag.DurationVar(&cfg.latencyJitter, "latency-jitter", envDuration("FAKE_LATENCY_JITTER", 0),
+		"random extra delay of every response")
+	flag.Float64Var(&cfg.errorRate, "error-rate", envFloat("FAKE_ERROR_RATE", 0),
+		"share of comics requests answered with 500, 502, 503 or 429")
+	flag.Float64Var(&cfg.lastErrorRate, "last-error-rate", envFloat("FAKE_LAST_ERROR_RATE", 0),
+		"share of latest comics requests answered with 500, 502, 503 or 429")
+	flag.StringVar(&missing, "missing", env("FAKE_MISSING", "404"), "comma separated ids answered with 404")
+	flag.StringVar(&malformed, "malformed", env("FAKE_MALFORMED", ""), "comma separated ids answered with broken json")
+	flag.IntVar(&cfg.count, "count", envInt("FAKE_COUNT", 0), "last comics id, biggest fixture id by default")
+	flag.Parse()
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	var err error
+	if cfg.missing, err = parseIDs(missing); err != nil {
+		log.Error("wrong missing ids", "error", err)
+		os.Exit(1)
+	}
+	if cfg.malformed, err = parseIDs(malformed); err != nil {
+		log.Error("wrong malformed ids", "error", err)
+		os.Exit(1)
+	}
+	if cfg.errorRate < 0 || cfg.errorRate > 1 || cfg.lastErrorRate < 0 || cfg.lastErrorRate > 1 {
+		log.Error("error rate should be within [0, 1]", "error_rate", cfg.errorRate,
+			"last_error_rate", cfg.lastErrorRate)
+		os.Exit(1)
+	}
+
+	srv, err := newServer(log, cfg)
+	if err != nil {
+		log.Error("failed to load fixtures", "error", err)
+		os.Exit(1)
+	}
+
+	httpServer := http.Server{
+		Addr:    cfg.address,
+		Handler: srv.routes(),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Debug("shutting down server")
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			log.Error("erroneous shutdown", "error", err)
+		}
+	}()
+
+	log.Info("fake xkcd is running", "address", cfg.address, "count", srv.cfg.count)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("server closed unexpectedly", "error", err)
+		os.Exit(1)
+	}
+}
+
+func newServer(log *slog.Logger, cfg config) (*server, error) {
+	var list []info
+	if err := json.Unmarshal(fixturesJSON, &list); err != nil {
+		return nil, err
+	}
+	fixtures := make(map[int]info, len(list))
+	last := 0
+	for _, comics := range list {
+		fixtures[comics.Num] = comics
+		last = max(last, comics.Num)
+	}
+	if cfg.count <= 0 {
+		cfg.count = last
+	}
+	return &server{log: log, cfg: cfg, fixtures: fixtures}, nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info.0.json", s.handleLast)
+	mux.HandleFunc("GET /{id}/info.0.json", s.handleComics)
+	return mux
+}
+
+// handleLast serves the latest comics, it is malformed if the latest comics
+// is.
+func (s *server) handleLast(w http.ResponseWriter, r *http.Request) {
+	if !s.delay(r.Context()) {
+		return
+	}
+	if s.injectError(w, s.cfg.lastErrorRate) {
+		s.log.Debug("injected error of latest comics")
+		return
+	}
+	if s.cfg.malformed[s.cfg.count] {
+		writeMalformed(w, s.cfg.count)
+		return
+	}
+	s.writeInfo(w, s.comics(s.cfg.count))
+}
+
+func (s *server) handleComics(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 || id > s.cfg.count || s.cfg.missing[id] {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.delay(r.Context()) {
+		return
+	}
+	if s.injectError(w, s.cfg.errorRate) {
+		s.log.Debug("injected error", "id", id)
+		return
+	}
+	if s.cfg.malformed[id] {
+		writeMalformed(w, id)
+		return
+	}
+	s.writeInfo(w, s.comics(id))
+}
+
+// injectError answers a share of requests given by rate with 500, 502, 503 or
+// 429, it returns true if the request has been answered.
+func (s *server) injectError(w http.ResponseWriter, rate float64) bool {
+	if rate <= 0 || rand.Float64() >= rate {
+		return false
+	}
+	status := []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusTooManyRequests,
+	}[rand.N(4)]
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "1")
+	}
+	http.Error(w, http.StatusText(status), status)
+	return true
+}
+
+func writeMalformed(w http.ResponseWriter, id int) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"num": %d, "title": "broken`, id)
+}
+
+// comics returns the fixture of the id or generates a deterministic one.
+func (s *server) comics(id int) info {
+	if comics, ok := s.fixtures[id]; ok {
+		return comics
+	}
+	title := "Comics " + strconv.Itoa(id)
+	return info{
+		Num:        id,
+		Year:       strconv.Itoa(2006 + id/150),
+		Month:      strconv.Itoa(1 + id%12),
+		Day:        strconv.Itoa(1 + id%28),
+		Title:      title,
+		SafeTitle:  title,
+		Alt:        "Generated comics number " + strconv.Itoa(id) + ".",
+		Transcript: "Generated transcript of comics number " + strconv.Itoa(id) + ".",
+		Img:        "https://imgs.xkcd.com/comics/generated_" + strconv.Itoa(id) + ".png",
+	}
+}
+
+func (s *server) writeInfo(w http.ResponseWriter, comics info) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comics); err != nil {
+		s.log.Error("failed to encode comics", "id", comics.Num, "error", err)
+	}
+}
+
+// delay sleeps for the configured latency, it returns false if the request
+// has been canceled meanwhile.
+func (s *server) delay(ctx context.Context) bool {
+	delay := s.cfg.latency
+	if s.cfg.latencyJitter > 0 {
+		delay += rand.N(s.cfg.latencyJitter)
+	}
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func parseIDs(list string) (map[int]bool, error) {
+	ids := make(map[int]bool)
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("wrong id %q: %w", field, err)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+func env(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func envFloat(key string, fallback float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, cfg config) string {
+	srv, err := newServer(slog.New(slog.DiscardHandler), cfg)
+	require.NoError(t, err)
+	server := httptest.NewServer(srv.routes())
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, body
+}
+
+func getInfo(t *testing.T, url string) info {
+	status, body := get(t, url)
+	require.Equal(t, http.StatusOK, status)
+	var comics info
+	require.NoError(t, json.Unmarshal(body, &comics))
+	return comics
+}
+
+func TestServer_Fixtures(t *testing.T) {
+	url := startServer(t, config{missing: map[int]bool{404: true}})
+
+	assert.Equal(t, 1168, getInfo(t, url+"/info.0.json").Num, "biggest fixture id is the latest comics")
+
+	python := getInfo(t, url+"/353/info.0.json")
+	assert.Equal(t, 353, python.Num)
+	assert.Equal(t, "Python", python.Title)
+	assert.Equal(t, "https://imgs.xkcd.com/comics/python.png", python.Img)
+
+	generated := getInfo(t, url+"/2/info.0.json")
+	assert.Equal(t, 2, generated.Num)
+	assert.Equal(t, "Comics 2", generated.Title)
+	assert.Equal(t, generated, getInfo(t, url+"/2/info.0.json"), "generated comics should be stable")
+
+	for _, path := range []string{"/404/info.0.json", "/1169/info.0.json", "/0/info.0.json", "/one/info.0.json"} {
+		status, _ := get(t, url+path)
+		assert.Equal(t, http.StatusNotFound, status, path)
+	}
+}
+
+func TestServer_Count(t *testing.T) {
+	url := startServer(t, config{count: 2000})
+
+	assert.Equal(t, 2000, getInfo(t, url+"/info.0.json").Num)
+	assert.Equal(t, 1500, getInfo(t, url+"/1500/info.0.json").Num)
+}
+
+func TestServer_Malformed(t *testing.T) {
+	url := startServer(t, config{count: 5, malformed: map[int]bool{3: true, 5: true}})
+
+	for _, path := range []string{"/3/info.0.json", "/info.0.json"} {
+		status, body := get(t, url+path)
+		require.Equal(t, http.StatusOK, status, path)
+		var comics info
+		assert.Error(t, json.Unmarshal(body, &comics), path)
+	}
+	assert.Equal(t, 4, getInfo(t, url+"/4/info.0.json").Num)
+}
+
+func TestServer_InjectedErrors(t *testing.T) {
+	injected := []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusTooManyRequests,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config
+		failed  string
+		served  string
+		missing string
+	}{
+		{
+			name:    "comics",
+			cfg:     config{errorRate: 1, missing: map[int]bool{404: true}},
+			failed:  "/353/info.0.json",
+			served:  "/info.0.json",
+			missing: "/404/info.0.json",
+		},
+		{
+			name:   "latest comics",
+			cfg:    config{lastErrorRate: 1},
+			failed: "/info.0.json",
+			served: "/353/info.0.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := startServer(t, tt.cfg)
+
+			for range 10 {
+				status, _ := get(t, url+tt.failed)
+				assert.Contains(t, injected, status)
+			}
+			getInfo(t, url+tt.served)
+			if tt.missing != "" {
+				status, _ := get(t, url+tt.missing)
+				assert.Equal(t, http.StatusNotFound, status, "missing comics are not found before errors")
+			}
+		})
+	}
+}
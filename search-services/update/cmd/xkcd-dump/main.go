@@ -0,0 +1,190 @@
+// Command xkcd-dump saves comics of a running xkcd compatible instance into a
+// dump that the update service reads with XKCD_URL=file://<dump>. The dump is
+// a directory of <id>/info.0.json files or, if -out ends with .tar.gz, an
+// archive of them.
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"yadro.com/course/update/adapters/xkcd"
+	"yadro.com/course/update/core"
+)
+
+type comics struct {
+	id   int
+	data []byte
+	err  error
+}
+
+func main() {
+	var (
+		url         string
+		out         string
+		from        int
+		concurrency int
+		timeout     time.Duration
+	)
+	flag.StringVar(&url, "url", "https://xkcd.com", "xkcd compatible instance to dump")
+	flag.StringVar(&out, "out", "xkcd-dump.tar.gz", "dump directory or .tar.gz archive")
+	flag.IntVar(&from, "from", 1, "first comics id to dump")
+	flag.IntVar(&concurrency, "concurrency", 10, "number of parallel requests")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "timeout of one request")
+	flag.Parse()
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, log, url, out, from, concurrency, timeout); err != nil {
+		fmt.Fprintln(os.Stderr, "dump failed:", err)
+		os.Exit(1)
+	}
+}
+
+func run(
+	ctx context.Context, log *slog.Logger, url, out string, from, concurrency int, timeout time.Duration,
+) error {
+	if concurrency < 1 {
+		return fmt.Errorf("wrong concurrency specified: %d", concurrency)
+	}
+	client, err := xkcd.NewClient(url, timeout, log)
+	if err != nil {
+		return err
+	}
+	last, err := client.LastID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get last comics id: %w", err)
+	}
+
+	w, err := newWriter(out)
+	if err != nil {
+		return err
+	}
+
+	ids := make(chan int)
+	results := make(chan comics)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				data, err := client.Raw(ctx, id)
+				results <- comics{id: id, data: data, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(ids)
+		for id := from; id <= last; id++ {
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var stored, failed int
+	for result := range results {
+		switch {
+		case errors.Is(result.err, core.ErrNotFound):
+			continue
+		case result.err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "comics %d: %v\n", result.id, result.err)
+			continue
+		}
+		if err := w.write(result.id, result.data); err != nil {
+			_ = w.close()
+			return fmt.Errorf("failed to write comics %d: %w", result.id, err)
+		}
+		stored++
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d comics have been dumped into %s\n", stored, out)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d comics could not be dumped", failed)
+	}
+	return nil
+}
+
+type writer interface {
+	write(id int, data []byte) error
+	close() error
+}
+
+func newWriter(out string) (writer, error) {
+	if !xkcd.IsArchive(out) {
+		return dirWriter(out), nil
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	return &archiveWriter{file: file, gz: gz, tar: tar.NewWriter(gz)}, nil
+}
+
+type dirWriter string
+
+func (d dirWriter) write(id int, data []byte) error {
+	dir := filepath.Join(string(d), strconv.Itoa(id))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "info.0.json"), data, 0o644)
+}
+
+func (d dirWriter) close() error {
+	return nil
+}
+
+type archiveWriter struct {
+	file *os.File
+	gz   *gzip.Writer
+	tar  *tar.Writer
+}
+
+func (a *archiveWriter) write(id int, data []byte) error {
+	err := a.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     strconv.Itoa(id) + "/info.0.json",
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = a.tar.Write(data)
+	return err
+}
+
+func (a *archiveWriter) close() error {
+	return errors.Join(a.tar.Close(), a.gz.Close(), a.file.Close())
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"yadro.com/course/update/adapters/xkcd"
+	"yadro.com/course/update/core"
+)
+
+func TestRun(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info.0.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"num": 3, "title": "Island (sketch)"}`))
+	})
+	mux.HandleFunc("GET /1/info.0.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"num": 1, "title": "Barrel - Part 1"}`))
+	})
+	mux.HandleFunc("GET /3/info.0.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"num": 3, "title": "Island (sketch)"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		out  string
+	}{
+		{name: "directory", out: "dump"},
+		{name: "archive", out: "dump.tar.gz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := slog.New(slog.DiscardHandler)
+			out := filepath.Join(t.TempDir(), tt.out)
+
+			require.NoError(t, run(ctx, log, server.URL, out, 1, 2, time.Second))
+
+			dump, err := xkcd.NewDump(out, log)
+			require.NoError(t, err)
+			last, err := dump.LastID(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 3, last)
+			info, err := dump.Get(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "Barrel - Part 1", info.Title)
+			_, err = dump.Get(ctx, 2)
+			assert.ErrorIs(t, err, core.ErrNotFound)
+		})
+	}
+}
@@ -12,15 +12,55 @@ type XKCD struct {
 	Concurrency int           `yaml:"concurrency" env:"XKCD_CONCURRENCY" env-default:"1"`
 	Timeout     time.Duration `yaml:"timeout" env:"XKCD_TIMEOUT" env-default:"10s"`
 	CheckPeriod time.Duration `yaml:"check_period" env:"XKCD_CHECK_PERIOD" env-default:"1h"`
+	CheckJitter time.Duration `yaml:"check_jitter" env:"XKCD_CHECK_JITTER" env-default:"5m"`
+}
+
+type Pipeline struct {
+	Normalize int `yaml:"normalize" env:"NORMALIZE_CONCURRENCY" env-default:"1"`
+	Store     int `yaml:"store" env:"STORE_CONCURRENCY" env-default:"1"`
+}
+
+type Retry struct {
+	Attempts  int           `yaml:"attempts" env:"RETRY_ATTEMPTS" env-default:"3"`
+	BaseDelay time.Duration `yaml:"base_delay" env:"RETRY_BASE_DELAY" env-default:"500ms"`
+	MaxDelay  time.Duration `yaml:"max_delay" env:"RETRY_MAX_DELAY" env-default:"10s"`
+}
+
+type Batch struct {
+	Size     int           `yaml:"size" env:"BATCH_SIZE" env-default:"100"`
+	Interval time.Duration `yaml:"interval" env:"BATCH_INTERVAL" env-default:"1s"`
+}
+
+type Trash struct {
+	TTL           time.Duration `yaml:"ttl" env:"TRASH_TTL" env-default:"168h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+type Outbox struct {
+	Interval  time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL" env-default:"5s"`
+	BatchSize int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	Retention time.Duration `yaml:"retention" env:"OUTBOX_RETENTION" env-default:"24h"`
+}
+
+type Stream struct {
+	Name   string        `yaml:"name" env:"STREAM_NAME" env-default:"XKCD_DB"`
+	MaxAge time.Duration `yaml:"max_age" env:"STREAM_MAX_AGE" env-default:"24h"`
 }
 
 type Config struct {
-	LogLevel      string `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
-	Address       string `yaml:"update_address" env:"UPDATE_ADDRESS" env-default:"localhost:80"`
-	XKCD          XKCD   `yaml:"xkcd"`
-	DBAddress     string `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
-	WordsAddress  string `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
-	BrokerAddress string `yaml:"broker_address" env:"BROKER_ADDRESS" env-default:"nats://localhost:4222"`
+	LogLevel      string   `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
+	Address       string   `yaml:"update_address" env:"UPDATE_ADDRESS" env-default:"localhost:80"`
+	Replica       string   `yaml:"replica" env:"REPLICA"`
+	XKCD          XKCD     `yaml:"xkcd"`
+	Pipeline      Pipeline `yaml:"pipeline"`
+	Retry         Retry    `yaml:"retry"`
+	Batch         Batch    `yaml:"batch"`
+	Trash         Trash    `yaml:"trash"`
+	Outbox        Outbox   `yaml:"outbox"`
+	DBAddress     string   `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
+	WordsAddress  string   `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"localhost:81"`
+	BrokerAddress string   `yaml:"broker_address" env:"BROKER_ADDRESS" env-default:"nats://localhost:4222"`
+	Stream        Stream   `yaml:"stream"`
 }
 
 func MustLoad(configPath string) Config {
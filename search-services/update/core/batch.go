@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// store saves comics coming from built in batches until built is closed, see
+// BatchPolicy. existingIDs tells which comics are updated, see process.
+func (s *Service) store(ctx context.Context, built <-chan Comics, existingIDs map[int]bool) {
+	size := max(s.batch.Size, 1)
+	batch := make([]Comics, 0, size)
+	var (
+		timer   *time.Timer
+		expired <-chan time.Time
+	)
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, expired = nil, nil
+		}
+		if len(batch) > 0 {
+			start := time.Now()
+			s.flush(ctx, batch, existingIDs)
+			s.track(func(p *UpdateProgress) { p.Timings.Store += time.Since(start) })
+			batch = make([]Comics, 0, size)
+		}
+	}
+
+	for {
+		select {
+		case comics, ok := <-built:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, comics)
+			switch {
+			case len(batch) >= size:
+				flush()
+			case len(batch) == 1 && s.batch.Interval > 0:
+				timer = time.NewTimer(s.batch.Interval)
+				expired = timer.C
+			}
+		case <-expired:
+			flush()
+		}
+	}
+}
+
+// flush stores the batch together with the event describing it. If the batch
+// fails for a non transient reason, comics are stored one by one, so a single
+// bad comics does not fail others.
+func (s *Service) flush(ctx context.Context, batch []Comics, existingIDs map[int]bool) {
+	event := s.storedEvent(batch, existingIDs)
+	err := s.retry(ctx, StageStore, func() error { return s.db.AddBatch(ctx, batch, event) })
+	if err == nil {
+		s.track(func(p *UpdateProgress) { p.Stored += len(batch) })
+		s.log.Info("Batch of " + strconv.Itoa(len(batch)) + " comics has been added to db")
+		s.notifyOutbox()
+		return
+	}
+	if len(batch) > 1 && !errors.Is(err, ErrTransient) && ctx.Err() == nil {
+		s.log.Warn("Failed to add batch of comics, adding them one by one", "error", err)
+		for _, comics := range batch {
+			s.flush(ctx, []Comics{comics}, existingIDs)
+		}
+		return
+	}
+	for _, comics := range batch {
+		s.log.Error("Failed to add comics "+strconv.Itoa(comics.ID)+" to db", "error", err)
+		s.track(func(p *UpdateProgress) { p.Failed++ })
+		s.recordFailure(ctx, comics.ID, err)
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewService_WrongBatchPolicy(t *testing.T) {
+	_, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{Size: -1}, 0)
+	assert.Error(t, err)
+}
+
+func TestService_Store_BySize(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("AddBatch", ctx, mock.MatchedBy(func(b []Comics) bool { return len(b) == 2 }),
+		DBEvent{Type: DBEventStored, Added: []int{1}, Updated: []int{2}}).Return(nil).Once()
+	db.On("AddBatch", ctx, mock.MatchedBy(func(b []Comics) bool { return len(b) == 1 }),
+		DBEvent{Type: DBEventStored, Added: []int{3}}).Return(nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{Size: 2}, 0)
+	require.NoError(t, err)
+
+	built := make(chan Comics, 3)
+	for id := 1; id <= 3; id++ {
+		built <- Comics{ID: id}
+	}
+	close(built)
+	service.store(ctx, built, map[int]bool{2: true})
+
+	db.AssertExpectations(t)
+	assert.Equal(t, 3, service.Progress(ctx).Stored)
+}
+
+func TestService_Store_ByInterval(t *testing.T) {
+	ctx := context.Background()
+
+	flushed := make(chan struct{})
+	db := &MockDB{}
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID == 1 }), mock.Anything).Return(nil).Once().
+		Run(func(mock.Arguments) { close(flushed) })
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{Size: 100, Interval: 10 * time.Millisecond}, 0)
+	require.NoError(t, err)
+
+	built := make(chan Comics)
+	done := make(chan struct{})
+	go func() {
+		service.store(ctx, built, nil)
+		close(done)
+	}()
+
+	built <- Comics{ID: 1}
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("batch has not been flushed by interval")
+	}
+	close(built)
+	<-done
+
+	db.AssertExpectations(t)
+}
+
+func TestService_Store_FallsBackToSingle(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("AddBatch", ctx, mock.MatchedBy(func(b []Comics) bool { return len(b) == 3 }), mock.Anything).
+		Return(errors.New("bad row")).Once()
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID != 2 }), mock.Anything).Return(nil).Twice()
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID == 2 }), mock.Anything).Return(errors.New("bad row")).Once()
+	db.On("AddFailed", ctx, mock.MatchedBy(func(f FailedComics) bool {
+		return f.ID == 2 && f.Stage == StageStore
+	})).Return(nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{Size: 3}, 0)
+	require.NoError(t, err)
+
+	built := make(chan Comics, 3)
+	for id := 1; id <= 3; id++ {
+		built <- Comics{ID: id}
+	}
+	close(built)
+	service.store(ctx, built, nil)
+
+	db.AssertExpectations(t)
+	progress := service.Progress(ctx)
+	assert.Equal(t, 2, progress.Stored)
+	assert.Equal(t, 1, progress.Failed)
+}
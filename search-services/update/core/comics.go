@@ -0,0 +1,54 @@
+package core
+
+import "context"
+
+// refetch downloads comics from..to again regardless of whether they are
+// stored.
+func (s *Service) refetch(ctx context.Context, from, to int) error {
+	s.log.Info("Start refetching comics", "from", from, "to", to)
+	lastID, err := s.xkcd.LastID(ctx)
+	if err != nil {
+		s.log.Error("Failed to get last comics id", "error", err)
+		return err
+	}
+	existingIDs, err := s.storedIDs(ctx)
+	if err != nil {
+		s.log.Error("Failed to get ids of stored comics", "error", err)
+		return err
+	}
+	version, err := s.normVersion(ctx)
+	if err != nil {
+		s.log.Error("Failed to get version of words analyzer", "error", err)
+		return err
+	}
+
+	var ids []int
+	for id := from; id <= min(to, lastID); id++ {
+		ids = append(ids, id)
+	}
+	return s.process(ctx, ids, existingIDs, version, s.download)
+}
+
+// Delete removes comics from..to together with their failures and returns
+// how many comics have been deleted. Like Drop, it fails with
+// ErrAlreadyExists while a job is running.
+func (s *Service) Delete(ctx context.Context, from, to int) (int, error) {
+	if from < 1 || to < from {
+		return 0, ErrBadArguments
+	}
+	var deleted []int
+	err := s.exclusive(ctx, func() error {
+		var err error
+		deleted, err = s.db.Delete(ctx, from, to)
+		return err
+	})
+	if err != nil {
+		s.log.Error("Failed to delete comics", "from", from, "to", to, "error", err)
+		return 0, err
+	}
+	s.log.Info("Comics have been deleted", "from", from, "to", to, "deleted", len(deleted))
+	if len(deleted) > 0 {
+		s.notifyOutbox()
+	}
+	return len(deleted), nil
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Refetch(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", mock.Anything).Return(3, nil)
+	db.On("IDs", mock.Anything).Return([]int{1, 2}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	xkcd.On("Get", mock.Anything, 2).Return(XKCDInfo{ID: 2, Title: "two"}, nil)
+	xkcd.On("Get", mock.Anything, 3).Return(XKCDInfo{ID: 3, Title: "three"}, nil)
+	words.On("Norm", mock.Anything, mock.AnythingOfType("string")).Return([]string{"word"}, nil)
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool { return c.ID == 2 }),
+		DBEvent{Type: DBEventStored, JobID: 6, Updated: []int{2}}).Return(nil)
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool { return c.ID == 3 }),
+		DBEvent{Type: DBEventStored, JobID: 6, Added: []int{3}}).Return(nil)
+	db.On("CreateJob", ctx, mock.MatchedBy(func(j Job) bool { return j.Kind == JobRefetch })).Return(int64(6), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Kind == JobRefetch && j.Outcome == JobSucceeded && j.Total == 2 && j.Stored == 2
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	job, err := service.Refetch(ctx, "admin", 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, JobRefetch, job.Kind)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+	xkcd.AssertExpectations(t)
+	assert.Len(t, service.outboxReady, 1)
+}
+
+func TestService_Refetch_BadArguments(t *testing.T) {
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Refetch(context.Background(), "admin", 0, 1)
+	assert.ErrorIs(t, err, ErrBadArguments)
+	_, err = service.Refetch(context.Background(), "admin", 5, 4)
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		from, to  int
+		deleted   []int
+		dbErr     error
+		wantErr   error
+		wantEvent bool
+	}{
+		{name: "single", from: 5, to: 5, deleted: []int{5}, wantEvent: true},
+		{name: "range", from: 1, to: 10, deleted: []int{1, 2, 3, 5, 8, 9, 10}, wantEvent: true},
+		{name: "nothing deleted", from: 1, to: 10},
+		{name: "db error", from: 1, to: 10, dbErr: errors.New("db error"), wantErr: errors.New("db error")},
+		{name: "bad range", from: 10, to: 1, wantErr: ErrBadArguments},
+		{name: "zero id", from: 0, to: 0, wantErr: ErrBadArguments},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &MockDB{}
+			db.On("Delete", ctx, tt.from, tt.to).Return(tt.deleted, tt.dbErr)
+
+			service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+				pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+			require.NoError(t, err)
+
+			deleted, err := service.Delete(ctx, tt.from, tt.to)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if errors.Is(tt.wantErr, ErrBadArguments) {
+					assert.ErrorIs(t, err, ErrBadArguments)
+					db.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+				}
+				assert.Empty(t, service.outboxReady)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.deleted), deleted)
+			if tt.wantEvent {
+				assert.Len(t, service.outboxReady, 1)
+			} else {
+				assert.Empty(t, service.outboxReady)
+			}
+		})
+	}
+}
@@ -1,7 +1,27 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 var ErrBadArguments = errors.New("arguments are not acceptable")
 var ErrAlreadyExists = errors.New("resource or task already exists")
 var ErrNotFound = errors.New("resource is not found")
+var ErrJobFinished = errors.New("job is already finished")
+
+// ErrTransient is wrapped by adapters around failures that may go away on
+// their own: timeouts, unavailable services, 5xx and 429 responses.
+var ErrTransient = errors.New("transient error")
+
+// InvalidPayloadError is returned by xkcd adapters when comics json cannot be
+// decoded or fails validation. Such comics are quarantined with the payload.
+type InvalidPayloadError struct {
+	ID      int
+	Payload []byte
+	Reason  string
+}
+
+func (e *InvalidPayloadError) Error() string {
+	return "invalid payload of comics " + strconv.Itoa(e.ID) + ": " + e.Reason
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Update starts a job fetching missing comics and returns right away. The job
+// is detached from ctx, so it keeps running when the caller goes away and can
+// be stopped only with CancelJob. If a job is already running, it is returned
+// together with ErrAlreadyExists. If a job is running on another replica,
+// only ErrAlreadyExists is returned.
+func (s *Service) Update(ctx context.Context, triggeredBy string) (Job, error) {
+	return s.startJob(ctx, JobUpdate, triggeredBy, s.update)
+}
+
+// RetryFailed starts a job processing again only comics from the failed
+// comics ledger. It shares the lock with Update, see Update for details.
+func (s *Service) RetryFailed(ctx context.Context, triggeredBy string) (Job, error) {
+	return s.startJob(ctx, JobRetry, triggeredBy, s.retryFailed)
+}
+
+// Reprocess starts a job normalizing again archived xkcd json of every stored
+// comics and rewriting its words, nothing is downloaded. It shares the lock
+// with Update, see Update for details.
+func (s *Service) Reprocess(ctx context.Context, triggeredBy string) (Job, error) {
+	return s.startJob(ctx, JobReprocess, triggeredBy, s.reprocess)
+}
+
+// Refetch starts a job downloading comics from..to again and storing them
+// over the stored ones, ids after the last xkcd comics are skipped. It shares
+// the lock with Update, see Update for details.
+func (s *Service) Refetch(ctx context.Context, triggeredBy string, from, to int) (Job, error) {
+	if from < 1 || to < from {
+		return Job{}, ErrBadArguments
+	}
+	return s.startJob(ctx, JobRefetch, triggeredBy, func(ctx context.Context) error {
+		return s.refetch(ctx, from, to)
+	})
+}
+
+// Refresh starts a job downloading again stored comics matching the filter
+// and storing only those whose content has changed upstream. It shares the
+// lock with Update, see Update for details.
+func (s *Service) Refresh(ctx context.Context, triggeredBy string, filter RefreshFilter) (Job, error) {
+	if filter.Days < 0 {
+		return Job{}, ErrBadArguments
+	}
+	return s.startJob(ctx, JobRefresh, triggeredBy, func(ctx context.Context) error {
+		return s.refresh(ctx, filter)
+	})
+}
+
+func (s *Service) startJob(
+	ctx context.Context, kind JobKind, triggeredBy string, run func(context.Context) error,
+) (Job, error) {
+	if ok := s.mu.TryLock(); !ok {
+		s.log.Error("service already runs update")
+		return s.runningJob(), ErrAlreadyExists
+	}
+	release, err := s.lock(ctx)
+	if err != nil {
+		s.mu.Unlock()
+		return Job{}, err
+	}
+
+	job := Job{Kind: kind, StartedAt: time.Now(), Outcome: JobRunning, TriggeredBy: triggeredBy}
+	id, err := s.db.CreateJob(ctx, job)
+	if err != nil {
+		release()
+		s.mu.Unlock()
+		s.log.Error("Failed to create update job", "error", err)
+		return Job{}, err
+	}
+	job.ID = id
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.resetProgress(0)
+	s.stateMu.Lock()
+	s.job = job
+	s.cancelJob = cancel
+	s.lastRun = job.StartedAt
+	s.stateMu.Unlock()
+	s.updateProcessing.Store(true)
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer s.mu.Unlock()
+		defer release()
+		defer s.updateProcessing.Store(false)
+		defer cancel()
+
+		s.log.Info("Start update job", "job", job.ID, "kind", kind, "triggered_by", triggeredBy)
+		err := run(jobCtx)
+		s.finishJob(jobCtx, err)
+	}()
+
+	return job, nil
+}
+
+func (s *Service) finishJob(ctx context.Context, err error) {
+	s.stateMu.Lock()
+	job := s.withProgress(s.job)
+	s.cancelJob = nil
+	s.stateMu.Unlock()
+
+	job.FinishedAt = time.Now()
+	switch {
+	case err == nil:
+		job.Outcome = JobSucceeded
+	case errors.Is(err, context.Canceled):
+		job.Outcome = JobCanceled
+	default:
+		job.Outcome = JobFailed
+		job.Error = err.Error()
+	}
+
+	s.log.Info("Update job has been finished", "job", job.ID, "outcome", job.Outcome)
+	if err := s.db.FinishJob(context.WithoutCancel(ctx), job); err != nil {
+		s.log.Error("Failed to save update job "+strconv.FormatInt(job.ID, 10), "error", err)
+	}
+
+	s.stateMu.Lock()
+	s.job = job
+	s.stateMu.Unlock()
+}
+
+func (s *Service) GetJob(ctx context.Context, id int64) (Job, error) {
+	if job := s.runningJob(); job.ID == id && job.Outcome == JobRunning {
+		return job, nil
+	}
+	job, err := s.db.GetJob(ctx, id)
+	if err != nil {
+		s.log.Error("Failed to get update job "+strconv.FormatInt(id, 10), "error", err)
+		return Job{}, err
+	}
+	return job, nil
+}
+
+func (s *Service) ListJobs(ctx context.Context, limit, offset int) ([]Job, error) {
+	if limit <= 0 || offset < 0 {
+		return nil, ErrBadArguments
+	}
+	jobs, err := s.db.ListJobs(ctx, limit, offset)
+	if err != nil {
+		s.log.Error("Failed to list update jobs", "error", err)
+		return nil, err
+	}
+	running := s.runningJob()
+	for i := range jobs {
+		if jobs[i].ID == running.ID && running.Outcome == JobRunning {
+			jobs[i] = running
+		}
+	}
+	return jobs, nil
+}
+
+// CancelJob stops the running job. Jobs that have already finished cannot be
+// cancelled, ErrJobFinished is returned for them.
+func (s *Service) CancelJob(ctx context.Context, id int64) error {
+	s.stateMu.Lock()
+	if s.cancelJob != nil && s.job.ID == id {
+		s.cancelJob()
+		s.stateMu.Unlock()
+		s.log.Info("Update job has been cancelled", "job", id)
+		return nil
+	}
+	s.stateMu.Unlock()
+
+	if _, err := s.db.GetJob(ctx, id); err != nil {
+		return err
+	}
+	return ErrJobFinished
+}
+
+// Shutdown cancels the running job and waits until it has saved its outcome,
+// so that the job is not left running in db when the service stops.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.stateMu.Lock()
+	if s.cancelJob != nil {
+		s.cancelJob()
+	}
+	s.stateMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runningJob returns the latest job started by this service with counters
+// taken from the current progress.
+func (s *Service) runningJob() Job {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if s.job.Outcome != JobRunning {
+		return s.job
+	}
+	return s.withProgress(s.job)
+}
+
+func (s *Service) withProgress(job Job) Job {
+	job.Total = s.progress.Total
+	job.Fetched = s.progress.Fetched
+	job.Normalized = s.progress.Normalized
+	job.Stored = s.progress.Stored
+	job.Failed = s.progress.Failed
+	job.Changed = s.progress.Changed
+	job.Timings = s.progress.Timings
+	return job
+}
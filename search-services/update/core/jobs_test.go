@@ -0,0 +1,276 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Update_Job(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", mock.Anything).Return(2, nil)
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	xkcd.On("Get", mock.Anything, 2).Return(XKCDInfo{ID: 2, Title: "two"}, nil)
+	words.On("Norm", mock.Anything, mock.AnythingOfType("string")).Return([]string{"two"}, nil)
+	db.On("AddBatch", mock.Anything, mock.Anything, DBEvent{Type: DBEventStored, JobID: 7, Added: []int{2}}).
+		Return(nil)
+	db.On("CreateJob", ctx, mock.MatchedBy(func(j Job) bool {
+		return j.Outcome == JobRunning && j.TriggeredBy == "admin"
+	})).Return(int64(7), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.ID == 7 && j.Outcome == JobSucceeded && j.Total == 1 && j.Stored == 1 && !j.FinishedAt.IsZero()
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	job, err := service.Update(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), job.ID)
+	assert.Equal(t, JobRunning, job.Outcome)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+	db.On("ListJobs", ctx, 1, 0).Return([]Job{}, nil)
+	assert.Equal(t, StatusIdle, service.Status(ctx).Status)
+}
+
+func TestService_Update_JobFailed(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+
+	xkcd.On("LastID", mock.Anything).Return(0, errors.New("xkcd error"))
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(1), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Outcome == JobFailed && j.Error == "xkcd error"
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, &MockWords{}, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "test")
+	require.NoError(t, err)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+}
+
+func TestService_Update_CreateJobError(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(0), errors.New("db error"))
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "test")
+	assert.Error(t, err)
+	assert.True(t, service.mu.TryLock(), "update lock should be released")
+}
+
+func TestService_Update_OutlivesCaller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	xkcd.On("LastID", mock.Anything).Return(1, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	})
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", mock.Anything, mock.Anything).Return(int64(1), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Outcome == JobSucceeded
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "test")
+	require.NoError(t, err)
+	<-started
+	cancel()
+	close(release)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+}
+
+func TestService_CancelJob(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	started := make(chan struct{})
+	xkcd.On("LastID", mock.Anything).Return(1, nil).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+	})
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(3), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.ID == 3 && j.Outcome == JobCanceled
+	})).Return(nil)
+	db.On("GetJob", ctx, int64(3)).Return(Job{ID: 3, Outcome: JobCanceled}, nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "test")
+	require.NoError(t, err)
+	<-started
+
+	require.NoError(t, service.CancelJob(ctx, 3))
+	service.jobs.Wait()
+
+	assert.ErrorIs(t, service.CancelJob(ctx, 3), ErrJobFinished)
+	db.AssertExpectations(t)
+}
+
+func TestService_Shutdown(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	started := make(chan struct{})
+	xkcd.On("LastID", mock.Anything).Return(1, nil).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+	})
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(4), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.ID == 4 && j.Outcome == JobCanceled
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "test")
+	require.NoError(t, err)
+	<-started
+
+	// the job has saved its outcome when Shutdown returns
+	require.NoError(t, service.Shutdown(ctx))
+	db.AssertExpectations(t)
+	assert.True(t, service.mu.TryLock(), "update lock should be released")
+}
+
+func TestService_Shutdown_Timeout(t *testing.T) {
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	xkcd.On("LastID", mock.Anything).Return(1, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	})
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", mock.Anything, mock.Anything).Return(int64(5), nil)
+	db.On("FinishJob", mock.Anything, mock.Anything).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(context.Background(), "test")
+	require.NoError(t, err)
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, service.Shutdown(ctx), context.Canceled)
+
+	close(release)
+	service.jobs.Wait()
+}
+
+func TestService_CancelJob_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("GetJob", ctx, int64(42)).Return(Job{}, ErrNotFound)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.CancelJob(ctx, 42), ErrNotFound)
+}
+
+func TestService_GetJob_Running(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	service.job = Job{ID: 5, Outcome: JobRunning}
+	service.resetProgress(10)
+	service.track(func(p *UpdateProgress) { p.Stored = 4 })
+
+	job, err := service.GetJob(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 10, job.Total)
+	assert.Equal(t, 4, job.Stored)
+	db.AssertNotCalled(t, "GetJob", mock.Anything, mock.Anything)
+
+	db.On("ListJobs", ctx, 10, 0).Return([]Job{{ID: 5, Outcome: JobRunning}, {ID: 4, Outcome: JobSucceeded}}, nil)
+	jobs, err := service.ListJobs(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, 4, jobs[0].Stored)
+	assert.Equal(t, JobSucceeded, jobs[1].Outcome)
+}
+
+func TestService_ListJobs_BadArguments(t *testing.T) {
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.ListJobs(context.Background(), 0, 0)
+	assert.ErrorIs(t, err, ErrBadArguments)
+	_, err = service.ListJobs(context.Background(), 10, -1)
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
@@ -0,0 +1,35 @@
+package core
+
+import "context"
+
+// lock takes the lock shared by replicas. ErrAlreadyExists is returned if
+// another replica holds it.
+func (s *Service) lock(ctx context.Context) (func(), error) {
+	release, ok, err := s.locker.TryLock(ctx)
+	if err != nil {
+		s.log.Error("Failed to take lock shared by replicas", "error", err)
+		return nil, err
+	}
+	if !ok {
+		s.log.Error("another replica already runs update")
+		return nil, ErrAlreadyExists
+	}
+	return release, nil
+}
+
+// exclusive runs f unless a job or another exclusive operation is running on
+// any replica, ErrAlreadyExists is returned then.
+func (s *Service) exclusive(ctx context.Context, f func() error) error {
+	if ok := s.mu.TryLock(); !ok {
+		s.log.Error("service already runs update")
+		return ErrAlreadyExists
+	}
+	defer s.mu.Unlock()
+
+	release, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return f()
+}
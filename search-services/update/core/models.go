@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type ServiceStatus string
 
 const (
@@ -7,10 +9,85 @@ const (
 	StatusIdle    ServiceStatus = "idle"
 )
 
+type ServiceState struct {
+	Status  ServiceStatus
+	LastRun time.Time
+	NextRun time.Time
+	// Holder is the replica running a job, it is empty if no replica does.
+	Holder LockHolder
+}
+
+// LockHolder is the replica holding the lock shared by replicas and the time
+// it has taken the lock.
+type LockHolder struct {
+	Replica string
+	Since   time.Time
+}
+
+type UpdateProgress struct {
+	Status     ServiceStatus
+	Total      int
+	Fetched    int
+	Normalized int
+	Stored     int
+	Failed     int
+	// Changed counts comics whose content differs from the stored one.
+	Changed   int
+	CurrentID int
+	Timings   StageTimings
+}
+
+// StageTimings is the time spent by workers of every pipeline stage on
+// comics, summed over workers and including delays between retries. Time
+// spent waiting for the next stage is not counted, so the busiest stage is the
+// bottleneck of the pipeline.
+type StageTimings struct {
+	Fetch     time.Duration
+	Normalize time.Duration
+	Store     time.Duration
+}
+
+type JobOutcome string
+
+const (
+	JobRunning   JobOutcome = "running"
+	JobSucceeded JobOutcome = "succeeded"
+	JobFailed    JobOutcome = "failed"
+	JobCanceled  JobOutcome = "canceled"
+)
+
+type JobKind string
+
+const (
+	JobUpdate    JobKind = "update"
+	JobRetry     JobKind = "retry"
+	JobReprocess JobKind = "reprocess"
+	JobRefetch   JobKind = "refetch"
+	JobRefresh   JobKind = "refresh"
+)
+
+type Job struct {
+	ID          int64
+	Kind        JobKind
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Outcome     JobOutcome
+	Total       int
+	Fetched     int
+	Normalized  int
+	Stored      int
+	Failed      int
+	Changed     int
+	Timings     StageTimings
+	Error       string
+	TriggeredBy string
+}
+
 type DBStats struct {
 	WordsTotal    int
 	WordsUnique   int
 	ComicsFetched int
+	ComicsFailed  int
 }
 
 type ServiceStats struct {
@@ -19,9 +96,21 @@ type ServiceStats struct {
 }
 
 type Comics struct {
-	ID    int
-	URL   string
-	Words []string
+	ID          int
+	URL         string
+	Title       string
+	SafeTitle   string
+	Description string
+	Transcript  string
+	Published   time.Time
+	Words       []string
+	// NormVersion is version of the words analyzer that produced Words.
+	NormVersion int
+	// Hash is sha256 of the comics content, see contentHash.
+	Hash string
+	// Raw is json of the comics as it is served by xkcd, it is archived to
+	// reprocess comics without downloading them again.
+	Raw []byte
 }
 
 type XKCDInfo struct {
@@ -31,4 +120,125 @@ type XKCDInfo struct {
 	Description string
 	SafeTitle   string
 	Transcript  string
+	Published   time.Time
+	// Raw is json the info has been decoded from, empty for comics made up by
+	// the service.
+	Raw []byte
+}
+
+type DBEventType string
+
+const (
+	DBEventStored   DBEventType = "stored"
+	DBEventDeleted  DBEventType = "deleted"
+	DBEventDropped  DBEventType = "dropped"
+	DBEventRestored DBEventType = "restored"
+)
+
+// DBEvent tells subscribers what has changed in db. Added, Updated and
+// Deleted are filled for stored and deleted events only, dropped and restored
+// events concern every comics.
+type DBEvent struct {
+	Type    DBEventType
+	JobID   int64
+	Added   []int
+	Updated []int
+	Deleted []int
+	// Time is when the change has been committed, it is set by db.
+	Time time.Time
+}
+
+// OutboxEvent is an event saved in db together with the change it describes
+// and waiting to be published, see RelayOutbox.
+type OutboxEvent struct {
+	ID       int64
+	Event    DBEvent
+	Attempts int
+}
+
+// RefreshFilter narrows comics checked for upstream changes. Zero filter
+// checks every stored comics, otherwise comics published within the last Days
+// days or, if EmptyTranscript is set, comics without transcript are checked.
+type RefreshFilter struct {
+	Days            int
+	EmptyTranscript bool
+}
+
+// RawComics is archived xkcd json of a stored comics.
+type RawComics struct {
+	ID      int
+	Payload []byte
+}
+
+type FailureStage string
+
+const (
+	StageFetch     FailureStage = "fetch"
+	StageNormalize FailureStage = "normalize"
+	StageStore     FailureStage = "store"
+)
+
+type ErrorClass string
+
+const (
+	ErrorTransient ErrorClass = "transient"
+	ErrorNotFound  ErrorClass = "not_found"
+	ErrorPermanent ErrorClass = "permanent"
+	ErrorInvalid   ErrorClass = "invalid"
+)
+
+// FailedComics is a comics that could not be stored even after retries.
+type FailedComics struct {
+	ID         int
+	Stage      FailureStage
+	ErrorClass ErrorClass
+	Error      string
+	Attempts   int
+	FailedAt   time.Time
+}
+
+// QuarantinedComics keeps xkcd payload of a comics that failed validation, so
+// it can be inspected without breaking the crawl.
+type QuarantinedComics struct {
+	ID            int
+	Payload       string
+	Reason        string
+	QuarantinedAt time.Time
+}
+
+// BatchPolicy describes how built comics are grouped before they are stored.
+// A batch is stored when it has Size comics or Interval has passed since its
+// first comics. Zero Size stores every comics on its own, zero Interval waits
+// for a full batch.
+type BatchPolicy struct {
+	Size     int
+	Interval time.Duration
+}
+
+// OutboxPolicy tells how events are relayed from the outbox. Pending events
+// are checked every Interval and right after comics have changed, at most
+// BatchSize events are published at once. Sent events are kept for
+// Retention.
+type OutboxPolicy struct {
+	Interval  time.Duration
+	BatchSize int
+	Retention time.Duration
+}
+
+// PipelinePolicy tells how many workers run every stage of a job: Fetch
+// workers download comics from xkcd, Normalize workers send their texts to
+// the words analyzer and Store workers save them in db.
+type PipelinePolicy struct {
+	Fetch     int
+	Normalize int
+	Store     int
+}
+
+// RetryPolicy describes how transient failures are retried. Delay before
+// attempt n is BaseDelay*2^(n-1) capped by MaxDelay, with up to half of it
+// replaced by random jitter.
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
 }
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+const defaultOutboxBatchSize = 100
+
+// RelayOutbox publishes events saved in the outbox until ctx is cancelled.
+// Every replica runs the relay, events are claimed in db, so each one is
+// published by a single replica. Events claimed by one replica are published
+// in the order they have been saved. If an event fails to be published, it is
+// retried with the retry policy backoff and events after it wait for it. An
+// event may be published more than once if marking it as sent fails.
+func (s *Service) RelayOutbox(ctx context.Context, policy OutboxPolicy) {
+	if policy.Interval <= 0 {
+		s.log.Info("Outbox relay is disabled")
+		return
+	}
+	failures := 0
+	for {
+		delay := policy.Interval
+		if err := s.relay(ctx, policy); err != nil {
+			failures++
+			delay = max(s.retryPolicy.delay(failures), policy.Interval)
+			s.log.Warn("Failed to relay events from outbox", "attempt", failures, "retry_in", delay, "error", err)
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.log.Info("Outbox relay has been stopped")
+			return
+		case <-s.outboxReady:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// relay publishes pending events and marks them as sent. Once something has
+// been sent, events sent before the retention period are purged.
+func (s *Service) relay(ctx context.Context, policy OutboxPolicy) error {
+	limit := policy.BatchSize
+	if limit <= 0 {
+		limit = defaultOutboxBatchSize
+	}
+	sent, err := s.db.ClaimEvents(ctx, limit, func(e OutboxEvent) error {
+		return s.publisher.SendDBChangedEvent(ctx, e.Event)
+	})
+	if err != nil {
+		return err
+	}
+	if sent == 0 {
+		return nil
+	}
+	s.log.Info("Events have been relayed from outbox", "sent", sent)
+	if sent == limit {
+		// more events may be pending
+		s.notifyOutbox()
+	}
+
+	if policy.Retention > 0 {
+		return s.db.PurgeOutbox(ctx, time.Now().Add(-policy.Retention))
+	}
+	return nil
+}
+
+// notifyOutbox wakes up RelayOutbox, events saved since its last run are
+// published without waiting for the interval.
+func (s *Service) notifyOutbox() {
+	select {
+	case s.outboxReady <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Relay(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	publisher := &MockPublisher{}
+	stored := DBEvent{Type: DBEventStored, JobID: 3, Added: []int{1}}
+	deleted := DBEvent{Type: DBEventDeleted, Deleted: []int{2}}
+	db.On("ClaimEvents", ctx, 10).Return([]OutboxEvent{{ID: 1, Event: stored}, {ID: 2, Event: deleted}}, nil)
+	publisher.On("SendDBChangedEvent", ctx, stored).Return(nil).Once()
+	publisher.On("SendDBChangedEvent", ctx, deleted).Return(nil).Once()
+	db.On("PurgeOutbox", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-time.Hour + time.Minute))
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.relay(ctx, OutboxPolicy{Interval: time.Second, BatchSize: 10, Retention: time.Hour}))
+	db.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+	assert.Empty(t, service.outboxReady)
+}
+
+func TestService_Relay_PublishError(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	publisher := &MockPublisher{}
+	first := DBEvent{Type: DBEventDropped}
+	db.On("ClaimEvents", ctx, 2).Return([]OutboxEvent{
+		{ID: 1, Event: first},
+		{ID: 2, Event: DBEvent{Type: DBEventRestored}},
+	}, nil)
+	publisher.On("SendDBChangedEvent", ctx, first).Return(errors.New("broker is down")).Once()
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	assert.Error(t, service.relay(ctx, OutboxPolicy{Interval: time.Second, BatchSize: 2}))
+	db.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+	db.AssertNotCalled(t, "PurgeOutbox", mock.Anything, mock.Anything)
+}
+
+func TestService_Relay_FullBatch(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	publisher := &MockPublisher{}
+	db.On("ClaimEvents", ctx, 1).Return([]OutboxEvent{{ID: 4, Event: DBEvent{Type: DBEventDropped}}}, nil)
+	publisher.On("SendDBChangedEvent", ctx, mock.Anything).Return(nil)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.relay(ctx, OutboxPolicy{Interval: time.Second, BatchSize: 1}))
+	assert.Len(t, service.outboxReady, 1, "more events may be pending")
+	db.AssertNotCalled(t, "PurgeOutbox", mock.Anything, mock.Anything)
+}
+
+func TestService_RelayOutbox(t *testing.T) {
+	db := &MockDB{}
+	publisher := &MockPublisher{}
+	event := DBEvent{Type: DBEventRestored}
+	sent := make(chan struct{})
+	// the event is saved after the first run and relayed without waiting for
+	// the interval
+	db.On("ClaimEvents", mock.Anything, defaultOutboxBatchSize).Return(nil, nil).Once()
+	db.On("ClaimEvents", mock.Anything, defaultOutboxBatchSize).Return([]OutboxEvent{{ID: 9, Event: event}}, nil).Once()
+	db.On("ClaimEvents", mock.Anything, defaultOutboxBatchSize).Return(nil, nil)
+	publisher.On("SendDBChangedEvent", mock.Anything, event).Run(func(mock.Arguments) { close(sent) }).Return(nil).Once()
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.RelayOutbox(ctx, OutboxPolicy{Interval: time.Hour})
+		close(done)
+	}()
+	service.notifyOutbox()
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("event has not been relayed")
+	}
+	cancel()
+	<-done
+	publisher.AssertExpectations(t)
+}
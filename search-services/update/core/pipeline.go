@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// process runs comics with the given ids through the fetch, normalize and
+// store stages, see PipelinePolicy. Stages are connected by channels holding
+// at most as many comics as the next stage has workers, so a slow stage holds
+// back the previous one instead of piling up comics in memory. load gets info
+// about the comics at the fetch stage, the normalize stage makes comics of it
+// normalized by the given version of the words analyzer and the store stage
+// saves them in batches, see store. Comics that could not be loaded, built or
+// stored are recorded as failed. Stored comics are reported to subscribers as
+// updated if they are in existingIDs and as added otherwise.
+func (s *Service) process(
+	ctx context.Context, ids []int, existingIDs map[int]bool, version int,
+	load func(context.Context, int) (XKCDInfo, error),
+) error {
+	s.resetProgress(len(ids))
+	s.log.Info("Start pipeline", "comics", len(ids),
+		"fetch", s.pipeline.Fetch, "normalize", s.pipeline.Normalize, "store", s.pipeline.Store)
+	started := time.Now()
+
+	queue := make(chan int, len(ids))
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+
+	fetched := make(chan XKCDInfo, s.pipeline.Normalize)
+	stage(s.pipeline.Fetch, fetched, func() { s.fetchStage(ctx, queue, fetched, load) })
+
+	built := make(chan Comics, s.pipeline.Store)
+	stage(s.pipeline.Normalize, built, func() { s.normalizeStage(ctx, fetched, built, version) })
+
+	var wg sync.WaitGroup
+	for range s.pipeline.Store {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.store(ctx, built, existingIDs)
+		}()
+	}
+	wg.Wait()
+
+	timings := s.Progress(ctx).Timings
+	s.log.Info("End pipeline", "elapsed", time.Since(started),
+		"fetch", timings.Fetch, "normalize", timings.Normalize, "store", timings.Store)
+	return ctx.Err()
+}
+
+// stage runs work in the given number of goroutines and closes out when all of
+// them are done.
+func stage[T any](workers int, out chan<- T, work func()) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+}
+
+// fetchStage loads comics with ids taken from queue until it is empty or ctx
+// is done.
+func (s *Service) fetchStage(
+	ctx context.Context, queue <-chan int, fetched chan<- XKCDInfo,
+	load func(context.Context, int) (XKCDInfo, error),
+) {
+	for id := range queue {
+		if ctx.Err() != nil {
+			return
+		}
+		s.track(func(p *UpdateProgress) { p.CurrentID = id })
+		start := time.Now()
+		info, err := load(ctx, id)
+		s.track(func(p *UpdateProgress) { p.Timings.Fetch += time.Since(start) })
+		if errors.Is(err, errUnchanged) {
+			continue
+		}
+		if err != nil {
+			s.fail(ctx, id, err)
+			continue
+		}
+		fetched <- info
+	}
+}
+
+// normalizeStage builds comics of info taken from fetched until it is
+// closed. Info left in fetched after ctx is done is dropped, so fetch workers
+// are never blocked.
+func (s *Service) normalizeStage(ctx context.Context, fetched <-chan XKCDInfo, built chan<- Comics, version int) {
+	for info := range fetched {
+		if ctx.Err() != nil {
+			continue
+		}
+		start := time.Now()
+		comics, err := s.buildComics(ctx, info)
+		s.track(func(p *UpdateProgress) { p.Timings.Normalize += time.Since(start) })
+		if err != nil {
+			s.fail(ctx, info.ID, err)
+			continue
+		}
+		comics.NormVersion = version
+		built <- comics
+	}
+}
+
+func (s *Service) fail(ctx context.Context, id int, err error) {
+	s.log.Error("Failed to add comics "+strconv.Itoa(id)+" to db", "error", err)
+	s.track(func(p *UpdateProgress) { p.Failed++ })
+	s.recordFailure(ctx, id, err)
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Process_Backpressure(t *testing.T) {
+	ctx := context.Background()
+
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	db := &MockDB{}
+
+	var loaded atomic.Int32
+	xkcd.On("Get", ctx, mock.Anything).Return(XKCDInfo{Title: "comics"}, nil).Run(func(mock.Arguments) {
+		loaded.Add(1)
+	})
+	release := make(chan struct{})
+	words.On("Norm", ctx, mock.Anything).Return([]string{"comics"}, nil).Run(func(mock.Arguments) {
+		<-release
+	})
+	db.On("AddBatch", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(),
+		PipelinePolicy{Fetch: 3, Normalize: 1, Store: 1}, RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	ids := make([]int, 20)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	done := make(chan error)
+	go func() { done <- service.process(ctx, ids, nil, 1, service.download) }()
+
+	// the normalize worker holds one comics, the channel one more and every
+	// fetch worker waits to pass one
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, int(loaded.Load()), 5)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 20, int(loaded.Load()))
+	assert.Equal(t, 20, service.Progress(ctx).Stored)
+}
+
+func TestService_Process_Timings(t *testing.T) {
+	ctx := context.Background()
+
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	db := &MockDB{}
+
+	sleep := func(mock.Arguments) { time.Sleep(10 * time.Millisecond) }
+	xkcd.On("Get", ctx, 1).Return(XKCDInfo{ID: 1, Title: "one"}, nil).Run(sleep)
+	words.On("Norm", ctx, "one").Return([]string{"one"}, nil).Run(sleep)
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID == 1 && c.NormVersion == 3 }), mock.Anything).
+		Return(nil).Run(sleep)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.process(ctx, []int{1}, nil, 3, service.download))
+	db.AssertExpectations(t)
+
+	timings := service.Progress(ctx).Timings
+	assert.GreaterOrEqual(t, timings.Fetch, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, timings.Normalize, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, timings.Store, 10*time.Millisecond)
+}
+
+func TestService_Process_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	xkcd.On("Get", ctx, mock.Anything).Return(XKCDInfo{Title: "comics"}, nil)
+	words.On("Norm", ctx, mock.Anything).Return([]string(nil), context.Canceled).Run(func(mock.Arguments) {
+		cancel()
+	})
+
+	service, err := NewService(slog.Default(), &MockDB{}, xkcd, words, &MockPublisher{}, newMemLocker(),
+		PipelinePolicy{Fetch: 4, Normalize: 1, Store: 1}, RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	err = service.process(ctx, ids, nil, 1, service.download)
+	assert.ErrorIs(t, err, context.Canceled)
+	words.AssertNumberOfCalls(t, "Norm", 1)
+}
@@ -2,31 +2,75 @@ package core
 
 import (
 	"context"
+	"time"
 )
 
 type Updater interface {
-	Update(context.Context) error
+	Update(ctx context.Context, triggeredBy string) (Job, error)
+	GetJob(ctx context.Context, id int64) (Job, error)
+	ListJobs(ctx context.Context, limit, offset int) ([]Job, error)
+	CancelJob(ctx context.Context, id int64) error
+	RetryFailed(ctx context.Context, triggeredBy string) (Job, error)
+	Reprocess(ctx context.Context, triggeredBy string) (Job, error)
+	Refetch(ctx context.Context, triggeredBy string, from, to int) (Job, error)
+	Refresh(ctx context.Context, triggeredBy string, filter RefreshFilter) (Job, error)
+	Delete(ctx context.Context, from, to int) (int, error)
+	ListFailed(context.Context) ([]FailedComics, error)
+	ListQuarantined(context.Context) ([]QuarantinedComics, error)
 	Stats(context.Context) (ServiceStats, error)
-	Status(context.Context) ServiceStatus
+	Status(context.Context) ServiceState
+	Progress(context.Context) UpdateProgress
+	WatchProgress(context.Context, time.Duration) <-chan UpdateProgress
 	Drop(context.Context) error
+	Restore(context.Context) (int, error)
 }
 
 type DB interface {
-	Add(context.Context, Comics) error
+	AddBatch(context.Context, []Comics, DBEvent) error
 	Stats(context.Context) (DBStats, error)
-	Drop(context.Context) error
+	Drop(context.Context) (int, error)
+	Restore(ctx context.Context, since time.Time) (int, error)
+	PurgeTrash(ctx context.Context, before time.Time) error
+	Delete(ctx context.Context, from, to int) ([]int, error)
 	IDs(context.Context) ([]int, error)
+	Hashes(context.Context, RefreshFilter) (map[int]string, error)
+	OutdatedIDs(ctx context.Context, version int) ([]int, error)
+	CreateJob(context.Context, Job) (int64, error)
+	FinishJob(context.Context, Job) error
+	GetJob(ctx context.Context, id int64) (Job, error)
+	ListJobs(ctx context.Context, limit, offset int) ([]Job, error)
+	AddFailed(context.Context, FailedComics) error
+	ListFailed(context.Context) ([]FailedComics, error)
+	Quarantine(context.Context, QuarantinedComics) error
+	ListQuarantined(context.Context) ([]QuarantinedComics, error)
+	ListRaw(context.Context) ([]RawComics, error)
+	GetRaw(ctx context.Context, id int) ([]byte, error)
+	ClaimEvents(ctx context.Context, limit int, send func(OutboxEvent) error) (int, error)
+	PurgeOutbox(ctx context.Context, before time.Time) error
 }
 
 type XKCD interface {
 	Get(context.Context, int) (XKCDInfo, error)
 	LastID(context.Context) (int, error)
+	Decode(id int, payload []byte) (XKCDInfo, error)
 }
 
 type Words interface {
 	Norm(ctx context.Context, phrase string) ([]string, error)
+	Version(context.Context) (int, error)
+}
+
+// Locker is a lock shared by every replica of the service, it lets only one
+// replica change comics at a time.
+type Locker interface {
+	// TryLock takes the lock without waiting and returns the function
+	// releasing it. ok is false if the lock is held by someone else.
+	TryLock(ctx context.Context) (release func(), ok bool, err error)
+	// Holder returns the replica holding the lock, ok is false if the lock
+	// is free.
+	Holder(ctx context.Context) (holder LockHolder, ok bool, err error)
 }
 
 type DBPublisher interface {
-	SendDBChangedEvent(context.Context) error
+	SendDBChangedEvent(context.Context, DBEvent) error
 }
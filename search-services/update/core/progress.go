@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+func (s *Service) Progress(ctx context.Context) UpdateProgress {
+	s.stateMu.Lock()
+	progress := s.progress
+	s.stateMu.Unlock()
+
+	progress.Status = StatusIdle
+	if s.updateProcessing.Load() {
+		progress.Status = StatusRunning
+	}
+	return progress
+}
+
+// WatchProgress sends progress snapshots to the returned channel until ctx is
+// done. The current state is sent right away, after that a snapshot is sent at
+// most once per interval and only if it differs from the previous one.
+func (s *Service) WatchProgress(ctx context.Context, interval time.Duration) <-chan UpdateProgress {
+	ch := make(chan UpdateProgress)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last UpdateProgress
+		first := true
+		for {
+			if current := s.Progress(ctx); first || current != last {
+				select {
+				case ch <- current:
+				case <-ctx.Done():
+					return
+				}
+				last, first = current, false
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (s *Service) resetProgress(total int) {
+	s.stateMu.Lock()
+	s.progress = UpdateProgress{Total: total}
+	s.stateMu.Unlock()
+}
+
+func (s *Service) track(f func(*UpdateProgress)) {
+	s.stateMu.Lock()
+	f(&s.progress)
+	s.stateMu.Unlock()
+}
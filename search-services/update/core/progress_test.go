@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Progress_Update(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", ctx).Return(4, nil)
+	db.On("IDs", ctx).Return([]int{1}, nil)
+	db.On("OutdatedIDs", ctx, 1).Return([]int{}, nil)
+	words.On("Version", ctx).Return(1, nil)
+	xkcd.On("Get", ctx, 2).Return(XKCDInfo{ID: 2, Title: "two"}, nil)
+	xkcd.On("Get", ctx, 3).Return(XKCDInfo{ID: 3, Title: "three"}, nil)
+	xkcd.On("Get", ctx, 4).Return(XKCDInfo{}, errors.New("xkcd error"))
+	words.On("Norm", ctx, mock.AnythingOfType("string")).Return([]string{"word"}, nil)
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID == 2 }), mock.Anything).Return(nil)
+	db.On("AddBatch", ctx, single(func(c Comics) bool { return c.ID == 3 }), mock.Anything).Return(errors.New("db error"))
+	db.On("AddFailed", ctx, mock.MatchedBy(func(f FailedComics) bool { return f.ID == 3 })).Return(nil)
+	db.On("AddFailed", ctx, mock.MatchedBy(func(f FailedComics) bool { return f.ID == 4 })).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.update(ctx))
+
+	progress := service.Progress(ctx)
+	assert.Equal(t, StatusIdle, progress.Status)
+	assert.Equal(t, 3, progress.Total)
+	assert.Equal(t, 2, progress.Fetched)
+	assert.Equal(t, 2, progress.Normalized)
+	assert.Equal(t, 1, progress.Stored)
+	assert.Equal(t, 2, progress.Failed)
+	assert.Equal(t, 4, progress.CurrentID)
+}
+
+func TestService_WatchProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	events := service.WatchProgress(ctx, time.Millisecond)
+
+	first := <-events
+	assert.Equal(t, UpdateProgress{Status: StatusIdle}, first)
+
+	service.updateProcessing.Store(true)
+	service.resetProgress(10)
+	service.track(func(p *UpdateProgress) { p.Fetched++ })
+
+	var next UpdateProgress
+	require.Eventually(t, func() bool {
+		next = <-events
+		return next.Fetched == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, StatusRunning, next.Status)
+	assert.Equal(t, 10, next.Total)
+
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// errUnchanged is returned by a load function of process for comics that
+// need not be stored again.
+var errUnchanged = errors.New("comics has not changed")
+
+// refresh downloads again stored comics matching the filter and normalizes
+// and stores only those whose content hash differs from the stored one.
+func (s *Service) refresh(ctx context.Context, filter RefreshFilter) error {
+	s.log.Info("Start refreshing comics", "days", filter.Days, "empty_transcript", filter.EmptyTranscript)
+	hashes, err := s.db.Hashes(ctx, filter)
+	if err != nil {
+		s.log.Error("Failed to get hashes of stored comics", "error", err)
+		return err
+	}
+	version, err := s.normVersion(ctx)
+	if err != nil {
+		s.log.Error("Failed to get version of words analyzer", "error", err)
+		return err
+	}
+
+	ids := make([]int, 0, len(hashes))
+	existingIDs := make(map[int]bool, len(hashes))
+	for id := range hashes {
+		ids = append(ids, id)
+		existingIDs[id] = true
+	}
+	slices.Sort(ids)
+	return s.process(ctx, ids, existingIDs, version, func(ctx context.Context, id int) (XKCDInfo, error) {
+		info, err := s.download(ctx, id)
+		if err != nil {
+			return XKCDInfo{}, err
+		}
+		if contentHash(info) == hashes[id] {
+			return XKCDInfo{}, errUnchanged
+		}
+		s.log.Info("Comics has changed upstream", "id", id)
+		s.track(func(p *UpdateProgress) { p.Changed++ })
+		return info, nil
+	})
+}
+
+// contentHash returns hex encoded sha256 of the stored fields of the comics.
+// Fields are joined with the unit separator, migration 000008 computes the
+// same hash for comics stored before.
+func contentHash(info XKCDInfo) string {
+	var published string
+	if !info.Published.IsZero() {
+		published = info.Published.Format(time.DateOnly)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		info.URL, info.Title, info.SafeTitle, info.Description, info.Transcript, published,
+	}, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	same := XKCDInfo{ID: 1, Title: "one", Transcript: "one"}
+	changed := XKCDInfo{ID: 2, Title: "two", Transcript: "now with transcript"}
+	unknown := XKCDInfo{ID: 3, Title: "three"}
+	filter := RefreshFilter{Days: 30, EmptyTranscript: true}
+
+	db.On("Hashes", mock.Anything, filter).Return(map[int]string{
+		1: contentHash(same),
+		2: contentHash(XKCDInfo{ID: 2, Title: "two"}),
+		3: "",
+	}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	xkcd.On("Get", mock.Anything, 1).Return(same, nil)
+	xkcd.On("Get", mock.Anything, 2).Return(changed, nil)
+	xkcd.On("Get", mock.Anything, 3).Return(unknown, nil)
+	words.On("Norm", mock.Anything, mock.AnythingOfType("string")).Return([]string{"word"}, nil)
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool {
+		return c.ID == 2 && c.Hash == contentHash(changed) && c.NormVersion == 1
+	}), mock.Anything).Return(nil).Once()
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool {
+		return c.ID == 3 && c.Hash == contentHash(unknown)
+	}), mock.Anything).Return(nil).Once()
+	db.On("CreateJob", ctx, mock.MatchedBy(func(j Job) bool { return j.Kind == JobRefresh })).Return(int64(8), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Outcome == JobSucceeded && j.Total == 3 && j.Fetched == 3 && j.Changed == 2 && j.Stored == 2 &&
+			j.Failed == 0
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(2),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	job, err := service.Refresh(ctx, "admin", filter)
+	require.NoError(t, err)
+	assert.Equal(t, JobRefresh, job.Kind)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+	xkcd.AssertExpectations(t)
+	db.AssertNotCalled(t, "AddFailed", mock.Anything, mock.Anything)
+}
+
+func TestService_Refresh_BadArguments(t *testing.T) {
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Refresh(context.Background(), "admin", RefreshFilter{Days: -1})
+	assert.ErrorIs(t, err, ErrBadArguments)
+}
+
+func TestContentHash(t *testing.T) {
+	info := XKCDInfo{
+		ID:          353,
+		URL:         "https://imgs.xkcd.com/comics/python.png",
+		Title:       "Python",
+		SafeTitle:   "Python",
+		Description: "I wrote 20 short programs in Python yesterday.",
+		Published:   time.Date(2007, 12, 5, 0, 0, 0, 0, time.UTC),
+	}
+	hash := contentHash(info)
+	assert.Len(t, hash, 64)
+
+	raw := info
+	raw.Raw = []byte(`{"num": 353}`)
+	raw.ID = 0
+	assert.Equal(t, hash, contentHash(raw), "only stored fields should be hashed")
+
+	info.Transcript = "[[A man is flying]]"
+	assert.NotEqual(t, hash, contentHash(info))
+}
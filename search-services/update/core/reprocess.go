@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// reprocess normalizes archived xkcd json of every stored comics again and
+// stores it over the old one. Comics stored before json has been archived are left as
+// they are.
+func (s *Service) reprocess(ctx context.Context) error {
+	s.log.Info("Start reprocessing archived comics")
+	raws, err := s.db.ListRaw(ctx)
+	if err != nil {
+		s.log.Error("Failed to get archived comics", "error", err)
+		return err
+	}
+	ids := make([]int, len(raws))
+	payloads := make(map[int][]byte, len(raws))
+	existingIDs := make(map[int]bool, len(raws))
+	for i, raw := range raws {
+		ids[i] = raw.ID
+		payloads[raw.ID] = raw.Payload
+		existingIDs[raw.ID] = true
+	}
+	version, err := s.normVersion(ctx)
+	if err != nil {
+		s.log.Error("Failed to get version of words analyzer", "error", err)
+		return err
+	}
+	return s.process(ctx, ids, existingIDs, version, func(ctx context.Context, id int) (XKCDInfo, error) {
+		return s.decode(id, payloads[id])
+	})
+}
+
+// archived decodes archived json of the stored comics, comics stored before
+// json has been archived are downloaded again.
+func (s *Service) archived(ctx context.Context, id int) (XKCDInfo, error) {
+	var payload []byte
+	err := s.retry(ctx, StageFetch, func() error {
+		var err error
+		payload, err = s.db.GetRaw(ctx, id)
+		return err
+	})
+	if errors.Is(err, ErrNotFound) {
+		return s.download(ctx, id)
+	}
+	if err != nil {
+		s.log.Error("Failed to get archived comics "+strconv.Itoa(id), "error", err)
+		return XKCDInfo{}, err
+	}
+	return s.decode(id, payload)
+}
+
+// decode decodes archived json of the comics, it replaces download for
+// comics that need not be downloaded again.
+func (s *Service) decode(id int, payload []byte) (XKCDInfo, error) {
+	info, err := s.xkcd.Decode(id, payload)
+	if err != nil {
+		s.log.Error("Failed to decode archived comics "+strconv.Itoa(id), "error", err)
+		return XKCDInfo{}, err
+	}
+	s.track(func(p *UpdateProgress) { p.Fetched++ })
+	return info, nil
+}
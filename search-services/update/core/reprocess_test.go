@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Reprocess(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	db.On("ListRaw", mock.Anything).Return([]RawComics{
+		{ID: 1, Payload: []byte(`{"num": 1}`)},
+		{ID: 2, Payload: []byte(`{"num": 2}`)},
+	}, nil)
+	xkcd.On("Decode", 1, []byte(`{"num": 1}`)).Return(XKCDInfo{ID: 1, Title: "one"}, nil)
+	xkcd.On("Decode", 2, []byte(`{"num": 2}`)).Return(XKCDInfo{}, &InvalidPayloadError{ID: 2, Reason: "bad"})
+	words.On("Norm", mock.Anything, "one").Return([]string{"one"}, nil)
+	words.On("Version", mock.Anything).Return(2, nil)
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool {
+		return c.ID == 1 && c.NormVersion == 2 && c.Words[0] == "one"
+	}), mock.Anything).Return(nil)
+	db.On("AddFailed", mock.Anything, mock.MatchedBy(func(f FailedComics) bool {
+		return f.ID == 2 && f.ErrorClass == ErrorInvalid
+	})).Return(nil)
+	db.On("Quarantine", mock.Anything, mock.MatchedBy(func(q QuarantinedComics) bool { return q.ID == 2 })).Return(nil)
+	db.On("CreateJob", ctx, mock.MatchedBy(func(j Job) bool { return j.Kind == JobReprocess })).Return(int64(4), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Kind == JobReprocess && j.Outcome == JobSucceeded && j.Total == 2 && j.Stored == 1 && j.Failed == 1
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(2),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	job, err := service.Reprocess(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, JobReprocess, job.Kind)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+	xkcd.AssertExpectations(t)
+	xkcd.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestService_Update_RenormalizesOutdated(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", ctx).Return(2, nil)
+	db.On("IDs", ctx).Return([]int{1, 2}, nil)
+	words.On("Version", ctx).Return(2, nil)
+	db.On("OutdatedIDs", ctx, 2).Return([]int{1, 2}, nil)
+	db.On("GetRaw", ctx, 1).Return([]byte(`{"num": 1}`), nil)
+	xkcd.On("Decode", 1, []byte(`{"num": 1}`)).Return(XKCDInfo{ID: 1, Title: "one"}, nil)
+	db.On("GetRaw", ctx, 2).Return(nil, ErrNotFound)
+	xkcd.On("Get", ctx, 2).Return(XKCDInfo{ID: 2, Title: "two", Raw: []byte(`{"num": 2}`)}, nil)
+	words.On("Norm", ctx, "one").Return([]string{"one"}, nil)
+	words.On("Norm", ctx, "two").Return([]string{"two"}, nil)
+	db.On("AddBatch", ctx, single(func(c Comics) bool {
+		return c.ID == 1 && c.NormVersion == 2 && c.Words[0] == "one"
+	}), DBEvent{Type: DBEventStored, Updated: []int{1}}).Return(nil)
+	db.On("AddBatch", ctx, single(func(c Comics) bool {
+		return c.ID == 2 && c.NormVersion == 2 && string(c.Raw) == `{"num": 2}`
+	}), DBEvent{Type: DBEventStored, Updated: []int{2}}).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.update(ctx))
+	db.AssertExpectations(t)
+	xkcd.AssertExpectations(t)
+	assert.Equal(t, 2, service.Progress(ctx).Stored)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// stageError tells at which stage processing of a comics has failed and how
+// many attempts have been made there.
+type stageError struct {
+	stage    FailureStage
+	attempts int
+	err      error
+}
+
+func (e *stageError) Error() string {
+	return string(e.stage) + ": " + e.err.Error()
+}
+
+func (e *stageError) Unwrap() error {
+	return e.err
+}
+
+// retry calls f until it succeeds, fails with a non transient error or the
+// policy runs out of attempts. Errors are wrapped into stageError.
+func (s *Service) retry(ctx context.Context, stage FailureStage, f func() error) error {
+	attempts := max(s.retryPolicy.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrTransient) || attempt >= attempts {
+			return &stageError{stage: stage, attempts: attempt, err: err}
+		}
+
+		delay := s.retryPolicy.delay(attempt)
+		s.log.Warn("Retrying after transient error", "stage", stage, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return &stageError{stage: stage, attempts: attempt, err: ctx.Err()}
+		}
+	}
+}
+
+// delay returns backoff before the attempt following the given one.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay << min(attempt-1, 30)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// recordFailure saves the comics that could not be processed into the failed
+// comics ledger, comics with invalid payload are quarantined as well.
+// Failures caused by cancellation of the job are not recorded.
+func (s *Service) recordFailure(ctx context.Context, id int, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	failure := FailedComics{
+		ID:         id,
+		Stage:      StageFetch,
+		ErrorClass: classify(err),
+		Error:      err.Error(),
+		Attempts:   1,
+		FailedAt:   time.Now(),
+	}
+	var stageErr *stageError
+	if errors.As(err, &stageErr) {
+		failure.Stage = stageErr.stage
+		failure.Attempts = stageErr.attempts
+		failure.Error = stageErr.err.Error()
+	}
+	if err := s.db.AddFailed(ctx, failure); err != nil {
+		s.log.Error("Failed to record failure of comics "+strconv.Itoa(id), "error", err)
+	}
+
+	var invalid *InvalidPayloadError
+	if errors.As(err, &invalid) {
+		quarantined := QuarantinedComics{
+			ID:            id,
+			Payload:       string(invalid.Payload),
+			Reason:        invalid.Reason,
+			QuarantinedAt: failure.FailedAt,
+		}
+		if err := s.db.Quarantine(ctx, quarantined); err != nil {
+			s.log.Error("Failed to quarantine comics "+strconv.Itoa(id), "error", err)
+		}
+	}
+}
+
+func (s *Service) ListQuarantined(ctx context.Context) ([]QuarantinedComics, error) {
+	quarantined, err := s.db.ListQuarantined(ctx)
+	if err != nil {
+		s.log.Error("Failed to list quarantined comics", "error", err)
+		return nil, err
+	}
+	return quarantined, nil
+}
+
+func (s *Service) ListFailed(ctx context.Context) ([]FailedComics, error) {
+	failed, err := s.db.ListFailed(ctx)
+	if err != nil {
+		s.log.Error("Failed to list failed comics", "error", err)
+		return nil, err
+	}
+	return failed, nil
+}
+
+func classify(err error) ErrorClass {
+	var invalid *InvalidPayloadError
+	switch {
+	case errors.As(err, &invalid):
+		return ErrorInvalid
+	case errors.Is(err, ErrTransient):
+		return ErrorTransient
+	case errors.Is(err, ErrNotFound):
+		return ErrorNotFound
+	}
+	return ErrorPermanent
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Attempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{5, 500 * time.Millisecond, time.Second},
+		{100, 500 * time.Millisecond, time.Second},
+	}
+	for _, tt := range tests {
+		for range 100 {
+			delay := policy.delay(tt.attempt)
+			assert.GreaterOrEqual(t, delay, tt.min, "attempt %d", tt.attempt)
+			assert.LessOrEqual(t, delay, tt.max, "attempt %d", tt.attempt)
+		}
+	}
+
+	assert.Zero(t, RetryPolicy{Attempts: 3}.delay(1))
+}
+
+func TestNewService_WrongRetryPolicy(t *testing.T) {
+	_, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{Attempts: -1}, BatchPolicy{}, 0)
+	assert.Error(t, err)
+}
+
+func TestService_Retry(t *testing.T) {
+	transient := fmt.Errorf("%w: timeout", ErrTransient)
+	permanent := errors.New("bad json")
+
+	tests := []struct {
+		name     string
+		errs     []error
+		attempts int
+		wantErr  error
+	}{
+		{name: "success", errs: []error{nil}, attempts: 1},
+		{name: "transient then success", errs: []error{transient, transient, nil}, attempts: 3},
+		{name: "transient exhausted", errs: []error{transient, transient, transient}, attempts: 3, wantErr: transient},
+		{name: "permanent is not retried", errs: []error{permanent}, attempts: 1, wantErr: permanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+				pipelineOf(1), RetryPolicy{Attempts: 3, BaseDelay: time.Microsecond, MaxDelay: time.Millisecond}, BatchPolicy{}, 0)
+			require.NoError(t, err)
+
+			calls := 0
+			err = service.retry(context.Background(), StageFetch, func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tt.attempts, calls)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			var stageErr *stageError
+			require.ErrorAs(t, err, &stageErr)
+			assert.Equal(t, StageFetch, stageErr.stage)
+			assert.Equal(t, tt.attempts, stageErr.attempts)
+		})
+	}
+}
+
+func TestService_Retry_Canceled(t *testing.T) {
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{Attempts: 3, BaseDelay: time.Hour}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = service.retry(ctx, StageStore, func() error { return ErrTransient })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestService_Update_RecordsFailure(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", ctx).Return(1, nil)
+	db.On("IDs", ctx).Return([]int{}, nil)
+	db.On("OutdatedIDs", ctx, 1).Return([]int{}, nil)
+	words.On("Version", ctx).Return(1, nil)
+	xkcd.On("Get", ctx, 1).Return(XKCDInfo{}, fmt.Errorf("%w: status 503", ErrTransient)).Times(2)
+	db.On("AddFailed", ctx, mock.MatchedBy(func(f FailedComics) bool {
+		return f.ID == 1 && f.Stage == StageFetch && f.ErrorClass == ErrorTransient && f.Attempts == 2
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{Attempts: 2, BaseDelay: time.Microsecond}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.update(ctx))
+	xkcd.AssertExpectations(t)
+	db.AssertExpectations(t)
+	assert.Equal(t, 1, service.Progress(ctx).Failed)
+}
+
+func TestService_Update_QuarantinesInvalidPayload(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	invalid := &InvalidPayloadError{ID: 1, Payload: []byte(`{"num": "one"}`), Reason: "num is not a number"}
+	xkcd.On("LastID", ctx).Return(1, nil)
+	db.On("IDs", ctx).Return([]int{}, nil)
+	db.On("OutdatedIDs", ctx, 1).Return([]int{}, nil)
+	words.On("Version", ctx).Return(1, nil)
+	xkcd.On("Get", ctx, 1).Return(XKCDInfo{}, invalid).Once()
+	db.On("AddFailed", ctx, mock.MatchedBy(func(f FailedComics) bool {
+		return f.ID == 1 && f.ErrorClass == ErrorInvalid && f.Attempts == 1
+	})).Return(nil)
+	db.On("Quarantine", ctx, mock.MatchedBy(func(q QuarantinedComics) bool {
+		return q.ID == 1 && q.Payload == `{"num": "one"}` && q.Reason == "num is not a number"
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{Attempts: 3, BaseDelay: time.Microsecond}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, service.update(ctx))
+	xkcd.AssertExpectations(t)
+	db.AssertExpectations(t)
+	assert.Equal(t, 1, service.Progress(ctx).Failed)
+}
+
+func TestService_RetryFailed(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	db.On("ListFailed", mock.Anything).Return([]FailedComics{{ID: 5, Stage: StageFetch}}, nil)
+	db.On("IDs", mock.Anything).Return([]int{1, 2}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	xkcd.On("Get", mock.Anything, 5).Return(XKCDInfo{ID: 5, Title: "five"}, nil)
+	words.On("Norm", mock.Anything, mock.AnythingOfType("string")).Return([]string{"five"}, nil)
+	db.On("AddBatch", mock.Anything, single(func(c Comics) bool { return c.ID == 5 }),
+		DBEvent{Type: DBEventStored, JobID: 2, Added: []int{5}}).Return(nil)
+	db.On("CreateJob", ctx, mock.MatchedBy(func(j Job) bool { return j.Kind == JobRetry })).Return(int64(2), nil)
+	db.On("FinishJob", mock.Anything, mock.MatchedBy(func(j Job) bool {
+		return j.Kind == JobRetry && j.Outcome == JobSucceeded && j.Stored == 1
+	})).Return(nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	job, err := service.RetryFailed(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, JobRetry, job.Kind)
+
+	service.jobs.Wait()
+	db.AssertExpectations(t)
+	xkcd.AssertNotCalled(t, "LastID", mock.Anything)
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, ErrorTransient, classify(fmt.Errorf("%w: timeout", ErrTransient)))
+	assert.Equal(t, ErrorNotFound, classify(&stageError{stage: StageFetch, attempts: 1, err: ErrNotFound}))
+	assert.Equal(t, ErrorPermanent, classify(errors.New("bad json")))
+	assert.Equal(t, ErrorInvalid, classify(&stageError{stage: StageFetch, attempts: 1, err: &InvalidPayloadError{ID: 1}}))
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Schedule runs Update every period until ctx is cancelled. Each interval is
+// extended by a random delay up to jitter, so replicas started together do not
+// hit xkcd at the same moment. A tick is skipped if an update is still running.
+func (s *Service) Schedule(ctx context.Context, period, jitter time.Duration) {
+	if period <= 0 {
+		s.log.Info("Scheduled updates are disabled")
+		return
+	}
+	defer s.setNextRun(time.Time{})
+
+	for {
+		delay := nextDelay(period, jitter)
+		s.setNextRun(time.Now().Add(delay))
+		s.log.Info("Next scheduled update", "in", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.log.Info("Scheduler has been stopped")
+			return
+		case <-timer.C:
+		}
+
+		s.setNextRun(time.Time{})
+		if _, err := s.Update(ctx, "scheduler"); err != nil {
+			if errors.Is(err, ErrAlreadyExists) {
+				s.log.Info("Skip scheduled update, previous one is still running")
+				continue
+			}
+			s.log.Error("Scheduled update failed", "error", err)
+		}
+	}
+}
+
+func (s *Service) setNextRun(t time.Time) {
+	s.stateMu.Lock()
+	s.nextRun = t
+	s.stateMu.Unlock()
+}
+
+func nextDelay(period, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return period
+	}
+	return period + rand.N(jitter)
+}
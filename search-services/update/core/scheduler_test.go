@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextDelay(t *testing.T) {
+	assert.Equal(t, time.Hour, nextDelay(time.Hour, 0))
+	assert.Equal(t, time.Hour, nextDelay(time.Hour, -time.Minute))
+
+	for i := 0; i < 100; i++ {
+		delay := nextDelay(time.Hour, time.Minute)
+		assert.GreaterOrEqual(t, delay, time.Hour)
+		assert.Less(t, delay, time.Hour+time.Minute)
+	}
+}
+
+func TestService_Schedule_Disabled(t *testing.T) {
+	db := &MockDB{}
+	db.On("ListJobs", mock.Anything, 1, 0).Return([]Job{}, nil)
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		service.Schedule(context.Background(), 0, time.Second)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler with zero period must return immediately")
+	}
+	assert.True(t, service.Status(context.Background()).NextRun.IsZero())
+}
+
+func TestService_Schedule_RunsUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+
+	ran := make(chan struct{}, 1)
+	xkcd.On("LastID", mock.Anything).Return(1, nil).Run(func(mock.Arguments) {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+	})
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", mock.Anything, mock.Anything).Return(int64(1), nil)
+	db.On("FinishJob", mock.Anything, mock.Anything).Return(nil)
+	db.On("ListJobs", mock.Anything, 1, 0).Return([]Job{}, nil)
+
+	service, err := NewService(slog.Default(), db, xkcd, words, &MockPublisher{}, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		service.Schedule(ctx, 10*time.Millisecond, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("scheduled update has not been started")
+	}
+
+	cancel()
+	<-done
+	service.jobs.Wait()
+	assert.False(t, service.Status(context.Background()).LastRun.IsZero())
+	assert.True(t, service.Status(context.Background()).NextRun.IsZero())
+}
+
+func TestService_Schedule_SkipsWhileRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	xkcd := &MockXKCD{}
+	service, err := NewService(slog.Default(), &MockDB{}, xkcd, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		service.Schedule(ctx, 5*time.Millisecond, 0)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	<-done
+	xkcd.AssertNotCalled(t, "LastID", mock.Anything)
+}
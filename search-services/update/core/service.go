@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const maxChunkSize = 4 * 1024
@@ -18,17 +20,42 @@ type Service struct {
 	xkcd        XKCD
 	words       Words
 	publisher   DBPublisher
-	concurrency int
+	locker      Locker
+	pipeline    PipelinePolicy
+	retryPolicy RetryPolicy
+	batch       BatchPolicy
+	trashTTL    time.Duration
 
 	updateProcessing atomic.Bool
 	mu               sync.Mutex
+
+	stateMu   sync.Mutex
+	lastRun   time.Time
+	nextRun   time.Time
+	progress  UpdateProgress
+	job       Job
+	cancelJob context.CancelFunc
+	jobs      sync.WaitGroup
+
+	// outboxReady wakes up RelayOutbox when events have been saved.
+	outboxReady chan struct{}
 }
 
 func NewService(
-	log *slog.Logger, db DB, xkcd XKCD, words Words, publisher DBPublisher, concurrency int,
+	log *slog.Logger, db DB, xkcd XKCD, words Words, publisher DBPublisher, locker Locker, pipeline PipelinePolicy,
+	retryPolicy RetryPolicy, batch BatchPolicy, trashTTL time.Duration,
 ) (*Service, error) {
-	if concurrency < 1 {
-		return nil, fmt.Errorf("wrong concurrency specified: %d", concurrency)
+	if pipeline.Fetch < 1 || pipeline.Normalize < 1 || pipeline.Store < 1 {
+		return nil, fmt.Errorf("wrong pipeline policy specified: %+v", pipeline)
+	}
+	if retryPolicy.Attempts < 0 || retryPolicy.BaseDelay < 0 || retryPolicy.MaxDelay < 0 {
+		return nil, fmt.Errorf("wrong retry policy specified: %+v", retryPolicy)
+	}
+	if batch.Size < 0 || batch.Interval < 0 {
+		return nil, fmt.Errorf("wrong batch policy specified: %+v", batch)
+	}
+	if trashTTL < 0 {
+		return nil, fmt.Errorf("wrong trash ttl specified: %s", trashTTL)
 	}
 	return &Service{
 		log:         log,
@@ -36,123 +63,198 @@ func NewService(
 		xkcd:        xkcd,
 		words:       words,
 		publisher:   publisher,
-		concurrency: concurrency,
+		locker:      locker,
+		pipeline:    pipeline,
+		retryPolicy: retryPolicy,
+		batch:       batch,
+		trashTTL:    trashTTL,
+		outboxReady: make(chan struct{}, 1),
 	}, nil
 }
 
-func (s *Service) Update(ctx context.Context) error {
-	if ok := s.mu.TryLock(); !ok {
-		s.log.Error("service already runs update")
-		return ErrAlreadyExists
-	}
-	defer s.mu.Unlock()
-
-	s.updateProcessing.Store(true)
-	defer s.updateProcessing.Store(false)
-
+// update fetches every comics missing in db and normalizes again comics
+// normalized by another version of the words analyzer. It runs synchronously
+// inside the job started by Update.
+func (s *Service) update(ctx context.Context) error {
 	s.log.Info("Start updating db")
 	lastId, err := s.xkcd.LastID(ctx)
 	if err != nil {
 		s.log.Error("Failed update db", "error", err)
 		return err
 	}
-	ids, err := s.db.IDs(ctx)
+	existingIDs, err := s.storedIDs(ctx)
 	if err != nil {
 		s.log.Error("Failed update db", "error", err)
 		return err
 	}
 
-	// Gorrutins
-	s.log.Info("Start gorutings")
-	jobs := make(chan int, lastId)
-
-	var wg sync.WaitGroup
-	for i := 0; i < s.concurrency; i++ {
-		wg.Add(1)
-		go worker(s, ctx, jobs, &wg)
-	}
-
-	existingIDs := make(map[int]bool)
-	for _, id := range ids {
-		existingIDs[id] = true
-	}
-
-	shouldSendEvent := false
+	var missing []int
 	for i := 1; i <= lastId; i++ {
 		if !existingIDs[i] {
-			jobs <- i
-			shouldSendEvent = true
+			missing = append(missing, i)
 		}
 	}
 
-	close(jobs)
-
-	wg.Wait()
-	s.log.Info("End gorutings")
+	version, err := s.normVersion(ctx)
+	if err != nil {
+		s.log.Error("Failed to get version of words analyzer", "error", err)
+		return err
+	}
+	outdated, err := s.db.OutdatedIDs(ctx, version)
+	if err != nil {
+		s.log.Error("Failed update db", "error", err)
+		return err
+	}
+	if len(outdated) > 0 {
+		s.log.Info("Comics normalized by another analyzer version will be normalized again",
+			"count", len(outdated), "version", version)
+	}
 
-	if shouldSendEvent {
-		if err := s.publisher.SendDBChangedEvent(ctx); err != nil {
-			s.log.Error("Error publishing db changed event")
+	load := func(ctx context.Context, id int) (XKCDInfo, error) {
+		if existingIDs[id] {
+			return s.archived(ctx, id)
 		}
+		return s.download(ctx, id)
 	}
+	return s.process(ctx, append(missing, outdated...), existingIDs, version, load)
+}
 
-	return nil
+// storedIDs returns set of ids of comics stored in db.
+func (s *Service) storedIDs(ctx context.Context) (map[int]bool, error) {
+	ids, err := s.db.IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existingIDs := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		existingIDs[id] = true
+	}
+	return existingIDs, nil
 }
 
-func worker(s *Service, ctx context.Context, jobs <-chan int, wg *sync.WaitGroup) {
-	defer wg.Done()
-	for job := range jobs {
-		comics, err := getComicsById(s, ctx, job)
-		if err != nil {
-			s.log.Error("Failed to add comics "+strconv.Itoa(job)+" to db", "error", err)
-			continue
-		}
-		err = s.db.Add(ctx, comics)
-		if err != nil {
-			s.log.Error("Failed to add comics "+strconv.Itoa(job)+" to db", "error", err)
-			continue
+// retryFailed processes again only comics from the failed comics ledger.
+func (s *Service) retryFailed(ctx context.Context) error {
+	s.log.Info("Start retrying failed comics")
+	failed, err := s.db.ListFailed(ctx)
+	if err != nil {
+		s.log.Error("Failed to get failed comics", "error", err)
+		return err
+	}
+	ids := make([]int, len(failed))
+	for i, f := range failed {
+		ids[i] = f.ID
+	}
+	existingIDs, err := s.storedIDs(ctx)
+	if err != nil {
+		s.log.Error("Failed to get ids of stored comics", "error", err)
+		return err
+	}
+	version, err := s.normVersion(ctx)
+	if err != nil {
+		s.log.Error("Failed to get version of words analyzer", "error", err)
+		return err
+	}
+	return s.process(ctx, ids, existingIDs, version, s.download)
+}
+
+// storedEvent describes the stored batch of comics for subscribers.
+func (s *Service) storedEvent(batch []Comics, existingIDs map[int]bool) DBEvent {
+	ids := make([]int, len(batch))
+	for i, comics := range batch {
+		ids[i] = comics.ID
+	}
+	slices.Sort(ids)
+
+	event := DBEvent{Type: DBEventStored, JobID: s.runningJob().ID}
+	for _, id := range slices.Compact(ids) {
+		if existingIDs[id] {
+			event.Updated = append(event.Updated, id)
+		} else {
+			event.Added = append(event.Added, id)
 		}
-		s.log.Info("Comics " + strconv.Itoa(job) + " has been added to db")
 	}
+	return event
 }
 
-func getComicsById(s *Service, ctx context.Context, i int) (Comics, error) {
+// download gets info about the comics from xkcd retrying transient failures.
+func (s *Service) download(ctx context.Context, i int) (XKCDInfo, error) {
 	s.log.Info("Load info about comics " + strconv.Itoa(i))
 	var comicsRaw XKCDInfo
 	if i == 404 {
 		comicsRaw = XKCDInfo{ID: i, Title: "404", Description: "Not found", SafeTitle: "404", Transcript: "Not found"}
 	} else {
-		var err error
-		comicsRaw, err = s.xkcd.Get(ctx, i)
+		err := s.retry(ctx, StageFetch, func() error {
+			var err error
+			comicsRaw, err = s.xkcd.Get(ctx, i)
+			return err
+		})
 		if err != nil {
 			s.log.Error("Failed load info about comics "+strconv.Itoa(i), "error", nil)
-			return Comics{}, err
+			return XKCDInfo{}, err
 		}
 	}
+	s.track(func(p *UpdateProgress) { p.Fetched++ })
+	return comicsRaw, nil
+}
+
+// buildComics normalizes texts of the comics info and makes comics to store.
+func (s *Service) buildComics(ctx context.Context, comicsRaw XKCDInfo) (Comics, error) {
+	finalNormalized, err := s.normalize(ctx, comicsRaw)
+	if err != nil {
+		return Comics{}, err
+	}
+
+	comics := Comics{
+		ID:          comicsRaw.ID,
+		URL:         comicsRaw.URL,
+		Title:       comicsRaw.Title,
+		SafeTitle:   comicsRaw.SafeTitle,
+		Description: comicsRaw.Description,
+		Transcript:  comicsRaw.Transcript,
+		Published:   comicsRaw.Published,
+		Words:       finalNormalized,
+		Hash:        contentHash(comicsRaw),
+		Raw:         comicsRaw.Raw,
+	}
+	return comics, nil
+}
+
+// normVersion returns version of the words analyzer.
+func (s *Service) normVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.retry(ctx, StageNormalize, func() error {
+		var err error
+		version, err = s.words.Version(ctx)
+		return err
+	})
+	return version, err
+}
 
-	s.log.Info("Starting normilize comics " + strconv.Itoa(i))
+// normalize turns texts of the comics into normalized words.
+func (s *Service) normalize(ctx context.Context, info XKCDInfo) ([]string, error) {
+	s.log.Info("Starting normilize comics " + strconv.Itoa(info.ID))
 
-	words := strings.Fields(comicsRaw.Title + " " + comicsRaw.Description + " " + comicsRaw.SafeTitle + " " + comicsRaw.Transcript)
+	words := strings.Fields(info.Title + " " + info.Description + " " + info.SafeTitle + " " + info.Transcript)
 	chunks := splitWordsIntoChunks(words, maxChunkSize)
 	var finalNormalized []string
 
 	for _, chunk := range chunks {
-		normalized, err := s.words.Norm(ctx, chunk)
+		var normalized []string
+		err := s.retry(ctx, StageNormalize, func() error {
+			var err error
+			normalized, err = s.words.Norm(ctx, chunk)
+			return err
+		})
 		if err != nil {
 			s.log.Error("failed to normalize chunk", "error", err)
-			return Comics{}, err
+			return nil, err
 		}
 		finalNormalized = append(finalNormalized, normalized...)
 	}
 
-	s.log.Info("End normilize comics " + strconv.Itoa(i))
-
-	comics := Comics{
-		ID:    comicsRaw.ID,
-		URL:   comicsRaw.URL,
-		Words: finalNormalized,
-	}
-	return comics, nil
+	s.log.Info("End normilize comics " + strconv.Itoa(info.ID))
+	s.track(func(p *UpdateProgress) { p.Normalized++ })
+	return finalNormalized, nil
 }
 
 func splitWordsIntoChunks(words []string, maxSize int) []string {
@@ -200,18 +302,39 @@ func (s *Service) Stats(ctx context.Context) (ServiceStats, error) {
 
 }
 
-func (s *Service) Status(ctx context.Context) ServiceStatus {
-	if s.updateProcessing.Load() {
-		return StatusRunning
+// Status returns state of the whole cluster: the service is running if any
+// replica runs a job, and LastRun is the start of the latest job of any
+// replica. NextRun is the next scheduled update of this replica. If the
+// cluster state cannot be gotten, state of this replica is returned.
+func (s *Service) Status(ctx context.Context) ServiceState {
+	s.stateMu.Lock()
+	state := ServiceState{
+		Status:  StatusIdle,
+		LastRun: s.lastRun,
+		NextRun: s.nextRun,
 	}
-	return StatusIdle
+	s.stateMu.Unlock()
 
-}
+	if s.updateProcessing.Load() {
+		state.Status = StatusRunning
+	}
 
-func (s *Service) Drop(ctx context.Context) error {
-	if err := s.db.Drop(ctx); err != nil {
-		s.log.Error("Failed to delete information about comics", "error", err)
-		return err
+	holder, held, err := s.locker.Holder(ctx)
+	if err != nil {
+		s.log.Warn("Failed to get holder of lock shared by replicas", "error", err)
+		return state
+	}
+	if held {
+		state.Status = StatusRunning
+		state.Holder = holder
+	}
+	jobs, err := s.db.ListJobs(ctx, 1, 0)
+	if err != nil {
+		s.log.Warn("Failed to get latest update job", "error", err)
+		return state
+	}
+	if len(jobs) > 0 && jobs[0].StartedAt.After(state.LastRun) {
+		state.LastRun = jobs[0].StartedAt
 	}
-	return nil
+	return state
 }
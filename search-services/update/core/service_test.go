@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,11 +18,16 @@ type MockDB struct {
 	mock.Mock
 }
 
-func (m *MockDB) Add(ctx context.Context, comics Comics) error {
-	args := m.Called(ctx, comics)
+func (m *MockDB) AddBatch(ctx context.Context, batch []Comics, event DBEvent) error {
+	args := m.Called(ctx, batch, event)
 	return args.Error(0)
 }
 
+// single matches a batch of the only comics satisfying match.
+func single(match func(Comics) bool) any {
+	return mock.MatchedBy(func(batch []Comics) bool { return len(batch) == 1 && match(batch[0]) })
+}
+
 func (m *MockDB) IDs(ctx context.Context) ([]int, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]int), args.Error(1)
@@ -32,11 +38,160 @@ func (m *MockDB) Stats(ctx context.Context) (DBStats, error) {
 	return args.Get(0).(DBStats), args.Error(1)
 }
 
-func (m *MockDB) Drop(ctx context.Context) error {
+func (m *MockDB) Drop(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDB) Restore(ctx context.Context, since time.Time) (int, error) {
+	args := m.Called(ctx, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDB) PurgeTrash(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+func (m *MockDB) CreateJob(ctx context.Context, job Job) (int64, error) {
+	args := m.Called(ctx, job)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) FinishJob(ctx context.Context, job Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetJob(ctx context.Context, id int64) (Job, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *MockDB) ListJobs(ctx context.Context, limit, offset int) ([]Job, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]Job), args.Error(1)
+}
+
+func (m *MockDB) AddFailed(ctx context.Context, failed FailedComics) error {
+	args := m.Called(ctx, failed)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListFailed(ctx context.Context) ([]FailedComics, error) {
 	args := m.Called(ctx)
+	return args.Get(0).([]FailedComics), args.Error(1)
+}
+
+func (m *MockDB) Quarantine(ctx context.Context, quarantined QuarantinedComics) error {
+	args := m.Called(ctx, quarantined)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListQuarantined(ctx context.Context) ([]QuarantinedComics, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]QuarantinedComics), args.Error(1)
+}
+
+func (m *MockDB) ListRaw(ctx context.Context) ([]RawComics, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]RawComics), args.Error(1)
+}
+
+func (m *MockDB) Hashes(ctx context.Context, filter RefreshFilter) (map[int]string, error) {
+	args := m.Called(ctx, filter)
+	hashes, _ := args.Get(0).(map[int]string)
+	return hashes, args.Error(1)
+}
+
+func (m *MockDB) Delete(ctx context.Context, from, to int) ([]int, error) {
+	args := m.Called(ctx, from, to)
+	ids, _ := args.Get(0).([]int)
+	return ids, args.Error(1)
+}
+
+func (m *MockDB) GetRaw(ctx context.Context, id int) ([]byte, error) {
+	args := m.Called(ctx, id)
+	payload, _ := args.Get(0).([]byte)
+	return payload, args.Error(1)
+}
+
+func (m *MockDB) OutdatedIDs(ctx context.Context, version int) ([]int, error) {
+	args := m.Called(ctx, version)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// ClaimEvents passes events to send as the db adapter does, until the first
+// one that fails to be sent.
+func (m *MockDB) ClaimEvents(ctx context.Context, limit int, send func(OutboxEvent) error) (int, error) {
+	args := m.Called(ctx, limit)
+	events, _ := args.Get(0).([]OutboxEvent)
+	sent := 0
+	for _, e := range events {
+		if err := send(e); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, args.Error(1)
+}
+
+func (m *MockDB) PurgeOutbox(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
 	return args.Error(0)
 }
 
+// memLock is a lock shared by replicas in memory.
+type memLock struct {
+	mu     sync.Mutex
+	holder *LockHolder
+}
+
+// memLocker takes memLock on behalf of the replica.
+type memLocker struct {
+	lock    *memLock
+	replica string
+	err     error
+}
+
+func newMemLocker() *memLocker {
+	return &memLocker{lock: &memLock{}, replica: "replica"}
+}
+
+// replica returns locker of another replica sharing the lock.
+func (l *memLocker) replicaLocker(replica string) *memLocker {
+	return &memLocker{lock: l.lock, replica: replica}
+}
+
+func (l *memLocker) TryLock(context.Context) (func(), bool, error) {
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	l.lock.mu.Lock()
+	defer l.lock.mu.Unlock()
+	if l.lock.holder != nil {
+		return nil, false, nil
+	}
+	l.lock.holder = &LockHolder{Replica: l.replica, Since: time.Now()}
+	return func() {
+		l.lock.mu.Lock()
+		l.lock.holder = nil
+		l.lock.mu.Unlock()
+	}, true, nil
+}
+
+func (l *memLocker) Holder(context.Context) (LockHolder, bool, error) {
+	if l.err != nil {
+		return LockHolder{}, false, l.err
+	}
+	l.lock.mu.Lock()
+	defer l.lock.mu.Unlock()
+	if l.lock.holder == nil {
+		return LockHolder{}, false, nil
+	}
+	return *l.lock.holder, true, nil
+}
+
 type MockXKCD struct {
 	mock.Mock
 }
@@ -51,6 +206,11 @@ func (m *MockXKCD) Get(ctx context.Context, id int) (XKCDInfo, error) {
 	return args.Get(0).(XKCDInfo), args.Error(1)
 }
 
+func (m *MockXKCD) Decode(id int, payload []byte) (XKCDInfo, error) {
+	args := m.Called(id, payload)
+	return args.Get(0).(XKCDInfo), args.Error(1)
+}
+
 type MockWords struct {
 	mock.Mock
 }
@@ -60,35 +220,51 @@ func (m *MockWords) Norm(ctx context.Context, phrase string) ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockWords) Version(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 type MockPublisher struct {
 	mock.Mock
 }
 
-func (m *MockPublisher) SendDBChangedEvent(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *MockPublisher) SendDBChangedEvent(ctx context.Context, event DBEvent) error {
+	args := m.Called(ctx, event)
 	return args.Error(0)
 }
 
+// pipelineOf runs n workers at fetch and normalize stages and a single store
+// worker, so batches are stored in order.
+func pipelineOf(n int) PipelinePolicy {
+	return PipelinePolicy{Fetch: n, Normalize: n, Store: 1}
+}
+
 func TestNewService(t *testing.T) {
 	tests := []struct {
-		name        string
-		concurrency int
-		wantErr     bool
+		name     string
+		pipeline PipelinePolicy
+		wantErr  bool
 	}{
 		{
-			name:        "valid concurrency",
-			concurrency: 5,
-			wantErr:     false,
+			name:     "valid pipeline",
+			pipeline: PipelinePolicy{Fetch: 5, Normalize: 2, Store: 1},
+			wantErr:  false,
 		},
 		{
-			name:        "zero concurrency",
-			concurrency: 0,
-			wantErr:     true,
+			name:     "zero fetch workers",
+			pipeline: PipelinePolicy{Fetch: 0, Normalize: 1, Store: 1},
+			wantErr:  true,
 		},
 		{
-			name:        "negative concurrency",
-			concurrency: -1,
-			wantErr:     true,
+			name:     "zero normalize workers",
+			pipeline: PipelinePolicy{Fetch: 1, Normalize: 0, Store: 1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative store workers",
+			pipeline: PipelinePolicy{Fetch: 1, Normalize: 1, Store: -1},
+			wantErr:  true,
 		},
 	}
 
@@ -100,7 +276,8 @@ func TestNewService(t *testing.T) {
 			words := &MockWords{}
 			publisher := &MockPublisher{}
 
-			service, err := NewService(log, db, xkcd, words, publisher, tt.concurrency)
+			service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(), tt.pipeline,
+				RetryPolicy{}, BatchPolicy{}, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -108,7 +285,7 @@ func TestNewService(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, service)
-				assert.Equal(t, tt.concurrency, service.concurrency)
+				assert.Equal(t, tt.pipeline, service.pipeline)
 			}
 		})
 	}
@@ -125,6 +302,8 @@ func TestService_Update_Success(t *testing.T) {
 
 	xkcd.On("LastID", ctx).Return(3, nil)
 	db.On("IDs", ctx).Return([]int{1, 2}, nil)
+	db.On("OutdatedIDs", ctx, 1).Return([]int{}, nil)
+	words.On("Version", ctx).Return(1, nil)
 
 	comicsInfo := XKCDInfo{
 		ID:          3,
@@ -139,22 +318,20 @@ func TestService_Update_Success(t *testing.T) {
 
 	words.On("Norm", ctx, mock.AnythingOfType("string")).Return([]string{"test", "title", "description"}, nil)
 
-	db.On("Add", ctx, mock.MatchedBy(func(c Comics) bool {
+	db.On("AddBatch", ctx, single(func(c Comics) bool {
 		return c.ID == 3 && len(c.Words) > 0
-	})).Return(nil)
-
-	publisher.On("SendDBChangedEvent", ctx).Return(nil)
+	}), mock.Anything).Return(nil)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
-	err = service.Update(ctx)
+	err = service.update(ctx)
 	assert.NoError(t, err)
 
 	xkcd.AssertExpectations(t)
 	db.AssertExpectations(t)
 	words.AssertExpectations(t)
-	publisher.AssertExpectations(t)
 }
 
 func TestService_Update_AlreadyRunning(t *testing.T) {
@@ -166,7 +343,8 @@ func TestService_Update_AlreadyRunning(t *testing.T) {
 	words := &MockWords{}
 	publisher := &MockPublisher{}
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
 	service.mu.Lock()
@@ -176,7 +354,7 @@ func TestService_Update_AlreadyRunning(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		updateErr = service.Update(ctx)
+		_, updateErr = service.Update(ctx, "test")
 	}()
 
 	wg.Wait()
@@ -198,10 +376,11 @@ func TestService_Update_XKCDError(t *testing.T) {
 	expectedErr := errors.New("xkcd error")
 	xkcd.On("LastID", ctx).Return(0, expectedErr)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
-	err = service.Update(ctx)
+	err = service.update(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 
@@ -222,10 +401,11 @@ func TestService_Update_DBError(t *testing.T) {
 	expectedErr := errors.New("db error")
 	db.On("IDs", ctx).Return([]int{}, expectedErr)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
-	err = service.Update(ctx)
+	err = service.update(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 
@@ -245,6 +425,8 @@ func TestService_Update_Comics404(t *testing.T) {
 
 	xkcd.On("LastID", ctx).Return(5, nil)
 	db.On("IDs", ctx).Return([]int{1, 2, 3, 4}, nil)
+	db.On("OutdatedIDs", ctx, 1).Return([]int{}, nil)
+	words.On("Version", ctx).Return(1, nil)
 
 	comicsInfo5 := XKCDInfo{
 		ID:          5,
@@ -263,22 +445,20 @@ func TestService_Update_Comics404(t *testing.T) {
 		return strings.Contains(s, "404") || strings.Contains(s, "Not found")
 	})).Return([]string{"404", "not", "found"}, nil).Maybe()
 
-	db.On("Add", ctx, mock.MatchedBy(func(c Comics) bool {
+	db.On("AddBatch", ctx, single(func(c Comics) bool {
 		return c.ID == 5 || c.ID == 404
-	})).Return(nil)
+	}), mock.Anything).Return(nil)
 
-	publisher.On("SendDBChangedEvent", ctx).Return(nil)
-
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
-	err = service.Update(ctx)
+	err = service.update(ctx)
 	assert.NoError(t, err)
 
 	xkcd.AssertExpectations(t)
 	db.AssertExpectations(t)
 	words.AssertExpectations(t)
-	publisher.AssertExpectations(t)
 	xkcd.AssertNotCalled(t, "Get", ctx, 404)
 }
 
@@ -300,7 +480,8 @@ func TestService_Stats(t *testing.T) {
 	db.On("Stats", ctx).Return(dbStats, nil)
 	xkcd.On("LastID", ctx).Return(100, nil)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
 	stats, err := service.Stats(ctx)
@@ -324,7 +505,8 @@ func TestService_Stats_DBError(t *testing.T) {
 	expectedErr := errors.New("db stats error")
 	db.On("Stats", ctx).Return(DBStats{}, expectedErr)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
 	stats, err := service.Stats(ctx)
@@ -342,17 +524,143 @@ func TestService_Status(t *testing.T) {
 	xkcd := &MockXKCD{}
 	words := &MockWords{}
 	publisher := &MockPublisher{}
+	db.On("ListJobs", mock.Anything, 1, 0).Return([]Job{}, nil)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
-	assert.Equal(t, StatusIdle, service.Status(context.Background()))
+	assert.Equal(t, StatusIdle, service.Status(context.Background()).Status)
 
 	service.updateProcessing.Store(true)
-	assert.Equal(t, StatusRunning, service.Status(context.Background()))
+	assert.Equal(t, StatusRunning, service.Status(context.Background()).Status)
 
 	service.updateProcessing.Store(false)
-	assert.Equal(t, StatusIdle, service.Status(context.Background()))
+	assert.Equal(t, StatusIdle, service.Status(context.Background()).Status)
+}
+
+func TestService_Status_LastRun(t *testing.T) {
+	ctx := context.Background()
+	log := slog.Default()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	xkcd.On("LastID", mock.Anything).Return(1, nil)
+	db.On("IDs", mock.Anything).Return([]int{1}, nil)
+	db.On("OutdatedIDs", mock.Anything, 1).Return([]int{}, nil)
+	words.On("Version", mock.Anything).Return(1, nil)
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(1), nil)
+	db.On("FinishJob", mock.Anything, mock.Anything).Return(nil)
+	db.On("ListJobs", ctx, 1, 0).Return([]Job{}, nil)
+
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+	assert.True(t, service.Status(ctx).LastRun.IsZero())
+
+	before := time.Now()
+	_, err = service.Update(ctx, "test")
+	require.NoError(t, err)
+	service.jobs.Wait()
+
+	state := service.Status(ctx)
+	assert.Equal(t, StatusIdle, state.Status)
+	assert.False(t, state.LastRun.Before(before))
+	assert.True(t, state.NextRun.IsZero())
+}
+
+func TestService_Status_OtherReplica(t *testing.T) {
+	ctx := context.Background()
+
+	lastRun := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	db := &MockDB{}
+	db.On("ListJobs", ctx, 1, 0).Return([]Job{{ID: 3, StartedAt: lastRun, Outcome: JobRunning}}, nil)
+
+	locker := newMemLocker()
+	release, ok, err := locker.replicaLocker("update-2").TryLock(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, locker, pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	state := service.Status(ctx)
+	assert.Equal(t, StatusRunning, state.Status)
+	assert.Equal(t, "update-2", state.Holder.Replica)
+	assert.False(t, state.Holder.Since.IsZero())
+	assert.Equal(t, lastRun, state.LastRun)
+
+	release()
+	state = service.Status(ctx)
+	assert.Equal(t, StatusIdle, state.Status)
+	assert.Empty(t, state.Holder.Replica)
+}
+
+func TestService_Status_LockerError(t *testing.T) {
+	locker := newMemLocker()
+	locker.err = errors.New("db is down")
+	service, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, locker,
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	service.updateProcessing.Store(true)
+	assert.Equal(t, StatusRunning, service.Status(context.Background()).Status)
+}
+
+func TestService_LockedByOtherReplica(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	locker := newMemLocker()
+	_, ok, err := locker.replicaLocker("update-2").TryLock(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, locker, pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "admin")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = service.Reprocess(ctx, "admin")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	assert.ErrorIs(t, service.Drop(ctx), ErrAlreadyExists)
+	_, err = service.Restore(ctx)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = service.Delete(ctx, 1, 2)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	db.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "Drop", mock.Anything)
+
+	// the local lock is not kept when the shared one is held by another replica
+	assert.True(t, service.mu.TryLock())
+}
+
+func TestService_Update_ReleasesLock(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("CreateJob", ctx, mock.Anything).Return(int64(1), nil)
+	db.On("FinishJob", mock.Anything, mock.Anything).Return(nil)
+	xkcd := &MockXKCD{}
+	xkcd.On("LastID", mock.Anything).Return(0, errors.New("xkcd is down"))
+
+	locker := newMemLocker()
+	service, err := NewService(slog.Default(), db, xkcd, &MockWords{}, &MockPublisher{}, locker, pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, "admin")
+	require.NoError(t, err)
+	service.jobs.Wait()
+
+	_, held, err := locker.Holder(ctx)
+	require.NoError(t, err)
+	assert.False(t, held)
 }
 
 func TestService_Drop(t *testing.T) {
@@ -364,15 +672,18 @@ func TestService_Drop(t *testing.T) {
 	words := &MockWords{}
 	publisher := &MockPublisher{}
 
-	db.On("Drop", ctx).Return(nil)
+	db.On("PurgeTrash", ctx, mock.AnythingOfType("time.Time")).Return(nil)
+	db.On("Drop", ctx).Return(3, nil)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(), pipelineOf(2),
+		RetryPolicy{}, BatchPolicy{}, time.Hour)
 	require.NoError(t, err)
 
 	err = service.Drop(ctx)
 	assert.NoError(t, err)
 
 	db.AssertExpectations(t)
+	assert.Len(t, service.outboxReady, 1)
 }
 
 func TestService_Drop_Error(t *testing.T) {
@@ -385,9 +696,11 @@ func TestService_Drop_Error(t *testing.T) {
 	publisher := &MockPublisher{}
 
 	expectedErr := errors.New("drop error")
-	db.On("Drop", ctx).Return(expectedErr)
+	db.On("PurgeTrash", ctx, mock.AnythingOfType("time.Time")).Return(nil)
+	db.On("Drop", ctx).Return(0, expectedErr)
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
 	err = service.Drop(ctx)
@@ -395,9 +708,10 @@ func TestService_Drop_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 
 	db.AssertExpectations(t)
+	assert.Empty(t, service.outboxReady)
 }
 
-func TestGetComicsById_NormalizationError(t *testing.T) {
+func TestBuildComics_NormalizationError(t *testing.T) {
 	ctx := context.Background()
 	log := slog.Default()
 
@@ -406,7 +720,8 @@ func TestGetComicsById_NormalizationError(t *testing.T) {
 	words := &MockWords{}
 	publisher := &MockPublisher{}
 
-	service, err := NewService(log, db, xkcd, words, publisher, 2)
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
 	require.NoError(t, err)
 
 	comicsInfo := XKCDInfo{
@@ -418,20 +733,58 @@ func TestGetComicsById_NormalizationError(t *testing.T) {
 		URL:         "https://xkcd.com/1",
 	}
 
-	xkcd.On("Get", ctx, 1).Return(comicsInfo, nil)
-
 	expectedErr := errors.New("normalization error")
 	words.On("Norm", ctx, mock.AnythingOfType("string")).Return([]string{}, expectedErr)
 
-	comics, err := getComicsById(service, ctx, 1)
+	comics, err := service.buildComics(ctx, comicsInfo)
 	assert.Error(t, err)
-	assert.Equal(t, expectedErr, err)
+	assert.ErrorIs(t, err, expectedErr)
 	assert.Equal(t, Comics{}, comics)
 
-	xkcd.AssertExpectations(t)
 	words.AssertExpectations(t)
 }
 
+func TestBuildComics_Metadata(t *testing.T) {
+	ctx := context.Background()
+	log := slog.Default()
+
+	db := &MockDB{}
+	xkcd := &MockXKCD{}
+	words := &MockWords{}
+	publisher := &MockPublisher{}
+
+	service, err := NewService(log, db, xkcd, words, publisher, newMemLocker(),
+		pipelineOf(2), RetryPolicy{}, BatchPolicy{}, 0)
+	require.NoError(t, err)
+
+	published := time.Date(2008, time.April, 23, 0, 0, 0, 0, time.UTC)
+	comicsInfo := XKCDInfo{
+		ID:          410,
+		Title:       "Math Paper",
+		Description: "Alt text",
+		SafeTitle:   "Math Paper",
+		Transcript:  "Transcript",
+		URL:         "https://imgs.xkcd.com/comics/math_paper.png",
+		Published:   published,
+	}
+
+	words.On("Norm", ctx, mock.AnythingOfType("string")).Return([]string{"math", "paper"}, nil)
+
+	comics, err := service.buildComics(ctx, comicsInfo)
+	require.NoError(t, err)
+	assert.Equal(t, Comics{
+		ID:          410,
+		URL:         "https://imgs.xkcd.com/comics/math_paper.png",
+		Title:       "Math Paper",
+		SafeTitle:   "Math Paper",
+		Description: "Alt text",
+		Transcript:  "Transcript",
+		Published:   published,
+		Words:       []string{"math", "paper"},
+		Hash:        contentHash(comicsInfo),
+	}, comics)
+}
+
 func TestSplitWordsIntoChunks(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// Drop moves every stored comics into the trash, where it is kept for the
+// trash TTL and can be brought back with Restore. Comics that have expired
+// in the trash are purged first. Drop waits for no job, ErrAlreadyExists is
+// returned if a job is running on any replica.
+func (s *Service) Drop(ctx context.Context) error {
+	return s.exclusive(ctx, func() error { return s.drop(ctx) })
+}
+
+func (s *Service) drop(ctx context.Context) error {
+	if err := s.purgeTrash(ctx); err != nil {
+		return err
+	}
+	dropped, err := s.db.Drop(ctx)
+	if err != nil {
+		s.log.Error("Failed to delete information about comics", "error", err)
+		return err
+	}
+	s.log.Info("Comics have been moved into trash", "dropped", dropped)
+	if dropped > 0 {
+		s.notifyOutbox()
+	}
+	return nil
+}
+
+// Restore brings back comics of the last drop, if it has been made within the
+// trash TTL, and empties the trash. Comics stored after the drop are kept as they are. It returns the
+// number of restored comics. Like Drop, it fails with ErrAlreadyExists while
+// a job is running.
+func (s *Service) Restore(ctx context.Context) (int, error) {
+	var restored int
+	err := s.exclusive(ctx, func() error {
+		var err error
+		restored, err = s.restore(ctx)
+		return err
+	})
+	return restored, err
+}
+
+func (s *Service) restore(ctx context.Context) (int, error) {
+	restored, err := s.db.Restore(ctx, time.Now().Add(-s.trashTTL))
+	if err != nil {
+		s.log.Error("Failed to restore comics from trash", "error", err)
+		return 0, err
+	}
+	s.log.Info("Comics have been restored from trash", "restored", restored)
+	if restored > 0 {
+		s.notifyOutbox()
+	}
+	return restored, nil
+}
+
+// PurgeTrash deletes comics expired in the trash every period until ctx is
+// cancelled, so they are not kept until the next Drop.
+func (s *Service) PurgeTrash(ctx context.Context, period time.Duration) {
+	if period <= 0 {
+		s.log.Info("Trash purging is disabled")
+		return
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Trash purging has been stopped")
+			return
+		case <-ticker.C:
+		}
+		// failures are logged, the next tick tries again
+		_ = s.purgeTrash(ctx)
+	}
+}
+
+func (s *Service) purgeTrash(ctx context.Context) error {
+	if err := s.db.PurgeTrash(ctx, time.Now().Add(-s.trashTTL)); err != nil {
+		s.log.Error("Failed to purge trash", "error", err)
+		return err
+	}
+	return nil
+}
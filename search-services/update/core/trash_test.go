@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewService_WrongTrashTTL(t *testing.T) {
+	_, err := NewService(slog.Default(), &MockDB{}, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestService_Drop_PurgesExpired(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	publisher := &MockPublisher{}
+	earliest := time.Now().Add(-time.Hour)
+	db.On("PurgeTrash", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return !before.Before(earliest) && !before.After(time.Now().Add(-time.Hour))
+	})).Return(nil)
+	db.On("Drop", ctx).Return(0, nil)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, publisher, newMemLocker(), pipelineOf(1),
+		RetryPolicy{}, BatchPolicy{}, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, service.Drop(ctx))
+	db.AssertExpectations(t)
+	assert.Empty(t, service.outboxReady)
+}
+
+func TestService_Drop_PurgeError(t *testing.T) {
+	ctx := context.Background()
+
+	db := &MockDB{}
+	db.On("PurgeTrash", ctx, mock.Anything).Return(errors.New("db error"))
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, time.Hour)
+	require.NoError(t, err)
+
+	assert.Error(t, service.Drop(ctx))
+	db.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func TestService_Restore(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		restored  int
+		dbErr     error
+		wantErr   bool
+		wantEvent bool
+	}{
+		{name: "restored", restored: 42, wantEvent: true},
+		{name: "empty trash"},
+		{name: "db error", dbErr: errors.New("db error"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &MockDB{}
+			since := time.Now().Add(-24 * time.Hour)
+			db.On("Restore", ctx, mock.MatchedBy(func(at time.Time) bool {
+				return !at.Before(since)
+			})).Return(tt.restored, tt.dbErr)
+
+			service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+				pipelineOf(1), RetryPolicy{}, BatchPolicy{}, 24*time.Hour)
+			require.NoError(t, err)
+
+			restored, err := service.Restore(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.restored, restored)
+			}
+			db.AssertExpectations(t)
+			if tt.wantEvent {
+				assert.Len(t, service.outboxReady, 1)
+			} else {
+				assert.Empty(t, service.outboxReady)
+			}
+		})
+	}
+}
+
+func TestService_PurgeTrash(t *testing.T) {
+	db := &MockDB{}
+	purged := make(chan struct{})
+	// the first purge fails and is tried again on the next tick
+	db.On("PurgeTrash", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+	db.On("PurgeTrash", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-time.Hour + time.Minute))
+	})).Run(func(mock.Arguments) { close(purged) }).Return(nil).Once()
+	db.On("PurgeTrash", mock.Anything, mock.Anything).Return(nil)
+
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, time.Hour)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.PurgeTrash(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-purged:
+	case <-time.After(time.Second):
+		t.Fatal("trash has not been purged")
+	}
+	cancel()
+	<-done
+}
+
+func TestService_PurgeTrash_Disabled(t *testing.T) {
+	db := &MockDB{}
+	service, err := NewService(slog.Default(), db, &MockXKCD{}, &MockWords{}, &MockPublisher{}, newMemLocker(),
+		pipelineOf(1), RetryPolicy{}, BatchPolicy{}, time.Hour)
+	require.NoError(t, err)
+
+	service.PurgeTrash(context.Background(), 0)
+	db.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+}
@@ -8,6 +8,8 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strings"
+	"syscall"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -47,12 +49,44 @@ func run(cfg config.Config, log *slog.Logger) error {
 	if err != nil {
 		return fmt.Errorf("failed to connect to db: %v", err)
 	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close db", "error", err)
+		}
+	}()
 	if err := storage.Migrate(); err != nil {
 		return fmt.Errorf("failed to migrate db: %v", err)
 	}
 
-	// xkcd adapter
-	xkcd, err := xkcd.NewClient(cfg.XKCD.URL, cfg.XKCD.Timeout, log)
+	// lock shared by replicas
+	replica := cfg.Replica
+	if replica == "" {
+		if replica, err = os.Hostname(); err != nil {
+			return fmt.Errorf("failed to get replica name: %v", err)
+		}
+	}
+	locker := db.NewLocker(log, storage, replica)
+
+	// jobs left running are stale only if no replica runs a job
+	release, ok, err := locker.TryLock(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to take lock: %v", err)
+	}
+	if ok {
+		err := storage.InterruptJobs(context.Background())
+		release()
+		if err != nil {
+			return fmt.Errorf("failed to clean up update jobs: %v", err)
+		}
+	}
+
+	// xkcd adapter, file:// url switches it to a local dump
+	var source core.XKCD
+	if dumpPath, ok := strings.CutPrefix(cfg.XKCD.URL, "file://"); ok {
+		source, err = xkcd.NewDump(dumpPath, log)
+	} else {
+		source, err = xkcd.NewClient(cfg.XKCD.URL, cfg.XKCD.Timeout, log)
+	}
 	if err != nil {
 		return fmt.Errorf("failed create XKCD client: %v", err)
 	}
@@ -64,16 +98,57 @@ func run(cfg config.Config, log *slog.Logger) error {
 	}
 
 	// nats adapter
-	natsPublisher, err := nats.NewNatsPublisher(log, cfg.BrokerAddress)
+	natsPublisher, err := nats.NewNatsPublisher(log, cfg.BrokerAddress, cfg.Stream.Name, cfg.Stream.MaxAge)
 	if err != nil {
 		return fmt.Errorf("failed create Nats client: %v", err)
 	}
+	defer natsPublisher.Close()
 
 	// service
-	updater, err := core.NewService(log, storage, xkcd, words, natsPublisher, cfg.XKCD.Concurrency)
+	pipelinePolicy := core.PipelinePolicy{
+		Fetch:     cfg.XKCD.Concurrency,
+		Normalize: cfg.Pipeline.Normalize,
+		Store:     cfg.Pipeline.Store,
+	}
+	retryPolicy := core.RetryPolicy{
+		Attempts:  cfg.Retry.Attempts,
+		BaseDelay: cfg.Retry.BaseDelay,
+		MaxDelay:  cfg.Retry.MaxDelay,
+	}
+	batchPolicy := core.BatchPolicy{
+		Size:     cfg.Batch.Size,
+		Interval: cfg.Batch.Interval,
+	}
+	updater, err := core.NewService(
+		log, storage, source, words, natsPublisher, locker, pipelinePolicy, retryPolicy, batchPolicy,
+		cfg.Trash.TTL,
+	)
 	if err != nil {
 		return fmt.Errorf("failed create Update service: %v", err)
 	}
+	// running job saves its outcome before db is closed
+	defer func() {
+		if err := updater.Shutdown(context.Background()); err != nil {
+			log.Error("failed to stop update job", "error", err)
+		}
+	}()
+
+	// context for Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// scheduler
+	go updater.Schedule(ctx, cfg.XKCD.CheckPeriod, cfg.XKCD.CheckJitter)
+
+	// trash purging
+	go updater.PurgeTrash(ctx, cfg.Trash.PurgeInterval)
+
+	// outbox relay
+	go updater.RelayOutbox(ctx, core.OutboxPolicy{
+		Interval:  cfg.Outbox.Interval,
+		BatchSize: cfg.Outbox.BatchSize,
+		Retention: cfg.Outbox.Retention,
+	})
 
 	// grpc server
 	listener, err := net.Listen("tcp", cfg.Address)
@@ -85,10 +160,6 @@ func run(cfg config.Config, log *slog.Logger) error {
 	updatepb.RegisterUpdateServer(s, updategrpc.NewServer(updater))
 	reflection.Register(s)
 
-	// context for Ctrl-C
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
-
 	go func() {
 		<-ctx.Done()
 		log.Debug("shutting down server")
@@ -59,11 +59,18 @@ func (s *server) Norm(_ context.Context, in *wordspb.WordsRequest) (*wordspb.Wor
 		)
 	}
 
+	if in.KeepDuplicates {
+		return &wordspb.WordsReply{Words: words.NormAll(in.Phrase)}, nil
+	}
 	return &wordspb.WordsReply{
 		Words: words.Norm(in.Phrase),
 	}, nil
 }
 
+func (s *server) Version(_ context.Context, _ *emptypb.Empty) (*wordspb.VersionReply, error) {
+	return &wordspb.VersionReply{Version: words.Version}, nil
+}
+
 func main() {
 	cfg := LoadConfig()
 
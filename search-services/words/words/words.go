@@ -7,6 +7,12 @@ import (
 	"github.com/kljensen/snowball"
 )
 
+// Version of the analyzer. It must be increased whenever Norm starts to
+// return other words for the same phrase, e.g. stop words or stemmer options
+// are changed, so that comics normalized before are normalized again.
+// Version 2 has made comics keep every occurrence of a word, see NormAll.
+const Version = 2
+
 var forbittenWords []string = []string{"of", "the", "a", "and", "or",
 	"will", "would", "i", "me", "you", "your",
 	"he", "his", "him", "who", "it", "that",
@@ -42,19 +48,9 @@ func splitByNonAlphanumericUnicode(str string) []string {
 }
 
 func Norm(phrase string) []string {
-	words := splitByNonAlphanumericUnicode(phrase)
 	result := make(map[string]bool, 0)
-	for _, word := range words {
-		tmp, err := snowball.Stem(word, "english", true)
-		if err == nil {
-			if !isPermissioned[tmp] {
-				result[tmp] = true
-			}
-		} else {
-			if !isPermissioned[word] {
-				result[word] = true
-			}
-		}
+	for _, word := range NormAll(phrase) {
+		result[word] = true
 	}
 	answer := make([]string, 0)
 	for key := range result {
@@ -63,3 +59,21 @@ func Norm(phrase string) []string {
 
 	return answer
 }
+
+// NormAll normalizes the phrase like Norm, but keeps every occurrence of a
+// word in the phrase order, so term frequencies can be counted.
+func NormAll(phrase string) []string {
+	words := splitByNonAlphanumericUnicode(phrase)
+	answer := make([]string, 0, len(words))
+	for _, word := range words {
+		tmp, err := snowball.Stem(word, "english", true)
+		if err != nil {
+			tmp = word
+		}
+		if !isPermissioned[tmp] {
+			answer = append(answer, tmp)
+		}
+	}
+
+	return answer
+}
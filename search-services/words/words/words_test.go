@@ -144,6 +144,39 @@ func TestNormFunction(t *testing.T) {
 	}
 }
 
+func TestNormAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "keeps duplicates in phrase order",
+			input:    "World hello, hello world world",
+			expected: []string{"world", "hello", "hello", "world", "world"},
+		},
+		{
+			name:     "stems and filters stop words",
+			input:    "the running and the runs",
+			expected: []string{"run", "run"},
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormAll(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("NormAll(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestNormFiltersKnownForbiddenWords(t *testing.T) {
 	forbiddenTests := []struct {
 		word     string
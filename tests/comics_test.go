@@ -0,0 +1,74 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ComicsPage struct {
+	Comics []Comics `json:"comics"`
+	Total  int      `json:"total"`
+}
+
+func TestComics(t *testing.T) {
+	token := login(t)
+	_, err := update(token)
+	require.NoError(t, err, "could not run update")
+	waitIdle(t)
+	t.Run("get by id", ComicsGetByID)
+	t.Run("not found", ComicsNotFound)
+	t.Run("bad id", ComicsBadID)
+	t.Run("list", ComicsList)
+	t.Run("list desc", ComicsListDesc)
+}
+
+func ComicsGetByID(t *testing.T) {
+	resp, err := client.Get(address + "/api/comics/353")
+	require.NoError(t, err, "failed to get comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
+	var comic Comics
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&comic), "decode failed")
+	require.Equal(t, 353, comic.ID)
+	require.Equal(t, comicsURL(t, 353), comic.URL)
+}
+
+func ComicsNotFound(t *testing.T) {
+	resp, err := client.Get(address + "/api/comics/1000000")
+	require.NoError(t, err, "failed to get comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "need not found")
+}
+
+func ComicsBadID(t *testing.T) {
+	resp, err := client.Get(address + "/api/comics/asdf")
+	require.NoError(t, err, "failed to get comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "need bad request")
+}
+
+func ComicsList(t *testing.T) {
+	resp, err := client.Get(address + "/api/comics?offset=10&limit=5")
+	require.NoError(t, err, "failed to list comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
+	var page ComicsPage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page), "decode failed")
+	require.Len(t, page.Comics, 5)
+	require.Equal(t, 11, page.Comics[0].ID)
+	require.Equal(t, lastComics(t), page.Total, "every comics of xkcd is stored")
+}
+
+func ComicsListDesc(t *testing.T) {
+	resp, err := client.Get(address + "/api/comics?limit=2&order=desc")
+	require.NoError(t, err, "failed to list comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "need OK status")
+	var page ComicsPage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page), "decode failed")
+	require.Len(t, page.Comics, 2)
+	require.Greater(t, page.Comics[0].ID, page.Comics[1].ID)
+}
@@ -1,8 +1,11 @@
 package api_test
 
 import (
+	"cmp"
 	"encoding/json"
 	"net/http"
+	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,6 +14,11 @@ import (
 
 const address = "http://localhost:28080"
 
+// xkcdAddress is the xkcd the update service crawls. Expected comics are
+// taken from it, so tests pass against the fake xkcd of `make up-fake` as
+// well as against the real one.
+var xkcdAddress = cmp.Or(os.Getenv("XKCD_URL"), "http://localhost:28084")
+
 var client = http.Client{
 	Timeout: 10 * time.Minute,
 }
@@ -35,3 +43,29 @@ func TestPing(t *testing.T) {
 	require.Equal(t, "ok", reply.Replies["update"], "no db running")
 	require.Equal(t, "ok", reply.Replies["search"], "no search running")
 }
+
+type XKCDComics struct {
+	Num int    `json:"num"`
+	Img string `json:"img"`
+}
+
+func xkcdComics(t *testing.T, path string) XKCDComics {
+	t.Helper()
+	resp, err := client.Get(xkcdAddress + path)
+	require.NoError(t, err, "cannot get comics from xkcd")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "wrong status from xkcd")
+	var comics XKCDComics
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&comics), "decode failed")
+	return comics
+}
+
+// lastComics returns id of the last comics in xkcd.
+func lastComics(t *testing.T) int {
+	return xkcdComics(t, "/info.0.json").Num
+}
+
+// comicsURL returns url of the comics picture in xkcd.
+func comicsURL(t *testing.T, id int) string {
+	return xkcdComics(t, "/"+strconv.Itoa(id)+"/info.0.json").Img
+}
@@ -20,6 +20,7 @@ func TestSearchConcurrency(t *testing.T) {
 	token := login(t)
 	_, err := update(token)
 	require.NoError(t, err, "could not update")
+	waitIdle(t)
 	var countOK atomic.Int64
 	var countBusy atomic.Int64
 	for range numPacks {
@@ -53,6 +54,7 @@ func TestSearchRateLong(t *testing.T) {
 	token := login(t)
 	_, err := update(token)
 	require.NoError(t, err, "could not update")
+	waitIdle(t)
 	time.Sleep(30 * time.Second)
 	var wg sync.WaitGroup
 	wg.Add(numReq)
@@ -135,3 +137,49 @@ func TestDropDbNoToken(t *testing.T) {
 	defer resp.Body.Close()
 	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
+
+func TestRetryDbNoToken(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, address+"/api/db/retry", nil)
+	require.NoError(t, err, "cannot make request")
+	resp, err := client.Do(req)
+	require.NoError(t, err, "could not send retry command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestQuarantineNoToken(t *testing.T) {
+	resp, err := client.Get(address + "/api/db/quarantine")
+	require.NoError(t, err, "could not get quarantined comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestReprocessNoToken(t *testing.T) {
+	resp, err := client.Post(address+"/api/db/reprocess", "", nil)
+	require.NoError(t, err, "could not send reprocess command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRefetchNoToken(t *testing.T) {
+	resp, err := client.Post(address+"/api/db/comics/1/refetch", "", nil)
+	require.NoError(t, err, "could not send refetch command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestDeleteComicsNoToken(t *testing.T) {
+	req, err := http.NewRequest(http.MethodDelete, address+"/api/db/comics?from=1&to=10", nil)
+	require.NoError(t, err, "cannot make request")
+	resp, err := client.Do(req)
+	require.NoError(t, err, "could not send delete command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRestoreNoToken(t *testing.T) {
+	resp, err := client.Post(address+"/api/db/restore", "", nil)
+	require.NoError(t, err, "could not send restore command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -18,12 +18,29 @@ type UpdateStats struct {
 	WordsUnique   int `json:"words_unique"`
 	ComicsFetched int `json:"comics_fetched"`
 	ComicsTotal   int `json:"comics_total"`
+	ComicsFailed  int `json:"comics_failed"`
+}
+
+type FailedReply struct {
+	Total int `json:"total"`
 }
 
 type UpdateStatus struct {
 	Status string `json:"status"`
 }
 
+type Job struct {
+	ID          int64  `json:"id"`
+	Outcome     string `json:"outcome"`
+	Total       int    `json:"total"`
+	Stored      int    `json:"stored"`
+	TriggeredBy string `json:"triggered_by"`
+}
+
+type JobsReply struct {
+	Jobs []Job `json:"jobs"`
+}
+
 func TestEmptyDB(t *testing.T) {
 	prepare(t)
 }
@@ -54,18 +71,61 @@ func TestUpdate(t *testing.T) {
 	require.NoError(t, err2, "error from update")
 	require.NoError(t, err3, "erorr from status")
 	require.True(t,
-		res1 == http.StatusOK && res2 == http.StatusAccepted ||
-			res2 == http.StatusOK && res1 == http.StatusAccepted,
-		"wrong statuses from concurrent updates, expect ok && accepted",
+		res1 == http.StatusAccepted && res2 == http.StatusConflict ||
+			res2 == http.StatusAccepted && res1 == http.StatusConflict,
+		"wrong statuses from concurrent updates, expect accepted && conflict",
 	)
 	require.Equal(t, "running", res3, "need running status while update")
+	waitIdle(t)
+
+	resp, err := client.Get(address + "/api/db/jobs?limit=1")
+	require.NoError(t, err, "could not get jobs")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var jobs JobsReply
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&jobs), "cannot decode")
+	require.Len(t, jobs.Jobs, 1)
+	require.Equal(t, "succeeded", jobs.Jobs[0].Outcome)
+	require.Equal(t, "admin", jobs.Jobs[0].TriggeredBy)
+	require.Equal(t, jobs.Jobs[0].Total, jobs.Jobs[0].Stored)
+
 	st := stats(t)
 	require.Equal(t, st.ComicsTotal, st.ComicsFetched)
-	require.True(t, st.ComicsTotal > 3000, "there are more than 3000 comics in XKCD")
+	require.Equal(t, lastComics(t), st.ComicsTotal, "wrong number of comics in xkcd")
 	require.True(t, 1000 < st.WordsTotal, "not enough total words in DB")
 	require.True(t, 100 < st.WordsUnique, "not enough unique words in DB")
 
 	prepare(t)
+	require.Equal(t, st.ComicsFetched, restore(t), "dropped comics should be restored")
+	require.Equal(t, st, stats(t))
+
+	prepare(t)
+}
+
+func TestFailedComicsEmpty(t *testing.T) {
+	prepare(t)
+	resp, err := client.Get(address + "/api/db/failed")
+	require.NoError(t, err, "could not get failed comics")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var failed FailedReply
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&failed), "cannot decode")
+	require.Equal(t, 0, failed.Total)
+	require.Equal(t, 0, stats(t).ComicsFailed)
+}
+
+func TestJobNotFound(t *testing.T) {
+	resp, err := client.Get(address + "/api/db/jobs/999999999")
+	require.NoError(t, err, "could not get job")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCancelJobNoToken(t *testing.T) {
+	resp, err := client.Post(address+"/api/db/jobs/1/cancel", "", nil)
+	require.NoError(t, err, "could not send cancel command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
 func login(t *testing.T) string {
@@ -93,13 +153,28 @@ func prepare(t *testing.T) {
 
 	updateStats := stats(t)
 	require.Equal(t, 0, updateStats.ComicsFetched)
-	require.True(t, updateStats.ComicsTotal > 3000, "there are more than 3000 comics in XKCD")
+	require.Equal(t, lastComics(t), updateStats.ComicsTotal, "wrong number of comics in xkcd")
 	require.Equal(t, 0, updateStats.WordsTotal)
 	require.Equal(t, 0, updateStats.WordsUnique)
 	updateStatus, err := status()
 	require.Equal(t, "idle", updateStatus, err)
 }
 
+func restore(t *testing.T) int {
+	req, err := http.NewRequest(http.MethodPost, address+"/api/db/restore", nil)
+	require.NoError(t, err, "cannot make request")
+	req.Header.Add("Authorization", "Token "+login(t))
+	resp, err := client.Do(req)
+	require.NoError(t, err, "could not send restore command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reply struct {
+		Restored int `json:"restored"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply), "cannot decode")
+	return reply.Restored
+}
+
 // this must not contain t because it runs in a waited goroutine
 func update(token string) (int, error) {
 	req, err := http.NewRequest(http.MethodPost, address+"/api/db/update", nil)
@@ -115,6 +190,18 @@ func update(token string) (int, error) {
 	return resp.StatusCode, nil
 }
 
+func waitIdle(t *testing.T) {
+	for range 600 {
+		st, err := status()
+		require.NoError(t, err, "could not get status")
+		if st == "idle" {
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatal("update has not been finished in time")
+}
+
 // this must not contain t because it runs in a waited goroutine
 func status() (string, error) {
 	resp, err := client.Get(address + "/api/db/status")
@@ -141,3 +228,27 @@ func stats(t *testing.T) UpdateStats {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats), "cannot decode")
 	return stats
 }
+
+func TestDeleteComicsBadRange(t *testing.T) {
+	token := login(t)
+	req, err := http.NewRequest(http.MethodDelete, address+"/api/db/comics?from=10&to=1", nil)
+	require.NoError(t, err, "cannot make request")
+	req.Header.Add("Authorization", "Token "+token)
+	resp, err := client.Do(req)
+	require.NoError(t, err, "could not send delete command")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUpdateBadMode(t *testing.T) {
+	token := login(t)
+	for _, query := range []string{"mode=everything", "mode=refresh&days=-1", "mode=refresh&empty_transcript=maybe"} {
+		req, err := http.NewRequest(http.MethodPost, address+"/api/db/update?"+query, nil)
+		require.NoError(t, err, "cannot make request")
+		req.Header.Add("Authorization", "Token "+token)
+		resp, err := client.Do(req)
+		require.NoError(t, err, "could not send update command")
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}